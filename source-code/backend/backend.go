@@ -0,0 +1,34 @@
+// Package backend executes ULB's build/clean/status operations: against a
+// native backend binary installed under ~/.ulb, inside a rootless
+// container running a pinned per-distro OCI image, or by driving a
+// registered distro.Backend's build steps directly in-process.
+package backend
+
+import "io"
+
+// Backend runs one backend subcommand (build, clean, status) against the
+// project's Config.toml.
+//
+// When jsonOutput is true, the returned ReadCloser carries the structured
+// progress stream the caller parses; the backend's stderr still goes to
+// the process's own stderr. When jsonOutput is false, stdout/stderr are
+// connected directly and the returned ReadCloser is nil. Either way, wait
+// must be called to release the underlying process and obtain its exit
+// error.
+type Backend interface {
+	Run(command, arg string, jsonOutput bool) (stdout io.ReadCloser, wait func() error, err error)
+}
+
+// Name identifies a Backend implementation, as set by `backend = "..."`
+// in Config.toml or the --backend flag.
+type Name string
+
+const (
+	Native Name = "native"
+	OCI    Name = "oci"
+	// Distro drives a registered distro.Backend's build steps directly,
+	// without any ulb-backend binary at all. This is what makes a
+	// third-party distro registered only as a Go plugin or a
+	// ulb-distro-<name> helper actually buildable.
+	Distro Name = "distro"
+)
@@ -0,0 +1,108 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/distro"
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/events"
+)
+
+// isoOutputDir is where DistroBackend's MakeISO writes the finished
+// image, matching where NativeBackend/OCIBackend's ulb-backend binary
+// writes it.
+const isoOutputDir = "build/release"
+
+// DistroBackend runs a build by calling a distro.Backend's Bootstrap,
+// InstallPackages, RemovePackages and MakeISO directly, synthesizing the
+// same stage_begin/stage_end/artifact event stream a ulb-backend binary
+// would emit so the rest of the CLI (progress rendering, `ulb sbom`'s
+// rootfs-artifact watch) can't tell the difference. It only supports the
+// "build" command: a distro.Backend has no equivalent of clean or status.
+type DistroBackend struct {
+	Distro         distro.Backend
+	ImageName      string
+	Installer      string
+	Architecture   string
+	Packages       []string
+	PackagesRemove []string
+}
+
+func (d *DistroBackend) Run(command, arg string, jsonOutput bool) (io.ReadCloser, func() error, error) {
+	if command != "build" {
+		return nil, nil, fmt.Errorf("distro backend: %q is not supported; switch to the native or oci backend for clean/status", command)
+	}
+	if arg != "" {
+		return nil, nil, fmt.Errorf("distro backend: %q is not supported; switch to the native or oci backend for a release build", arg)
+	}
+
+	if !jsonOutput {
+		wait := func() error { return d.build(nil) }
+		return nil, wait, nil
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	emit := func(evt events.Event) {
+		evt.V = events.Version
+		data, err := json.Marshal(evt)
+		if err != nil {
+			return
+		}
+		pw.Write(append(data, '\n'))
+	}
+	go func() {
+		done <- d.build(emit)
+		pw.Close()
+	}()
+	wait := func() error { return <-done }
+	return pr, wait, nil
+}
+
+// build drives the bootstrap/install/remove/make-iso sequence, reporting
+// each step through emit if it is non-nil. Stages never nest or run
+// concurrently here, so the stage name doubles as its event ID.
+func (d *DistroBackend) build(emit func(events.Event)) error {
+	stage := func(name string, fn func() error) error {
+		if emit != nil {
+			emit(events.Event{Type: events.TypeStageBegin, ID: name, Stage: name})
+		}
+		if err := fn(); err != nil {
+			if emit != nil {
+				emit(events.Event{Type: events.TypeError, ID: name, Message: err.Error(), Severity: events.SeverityError})
+				emit(events.Event{Type: events.TypeStageEnd, ID: name, Stage: name})
+			}
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if emit != nil {
+			emit(events.Event{Type: events.TypeStageEnd, ID: name, Stage: name})
+		}
+		return nil
+	}
+
+	cfg := distro.Config{ImageName: d.ImageName, Installer: d.Installer, Architecture: d.Architecture}
+	if err := stage("bootstrap", func() error { return d.Distro.Bootstrap(cfg) }); err != nil {
+		return err
+	}
+	if len(d.Packages) > 0 {
+		if err := stage("install-packages", func() error { return d.Distro.InstallPackages(d.Packages) }); err != nil {
+			return err
+		}
+	}
+	if len(d.PackagesRemove) > 0 {
+		if err := stage("remove-packages", func() error { return d.Distro.RemovePackages(d.PackagesRemove) }); err != nil {
+			return err
+		}
+	}
+	if emit != nil {
+		emit(events.Event{Type: events.TypeArtifact, Artifact: "rootfs", Path: distro.RootfsDir()})
+	}
+	if err := stage("make-iso", func() error { return d.Distro.MakeISO(isoOutputDir) }); err != nil {
+		return err
+	}
+	if emit != nil {
+		emit(events.Event{Type: events.TypeArtifact, Artifact: "iso", Path: isoOutputDir})
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package backend
+
+import (
+	"io"
+	"os"
+	"os/exec"
+)
+
+// NativeBackend runs the downloaded ~/.ulb/backend binary directly on the
+// host. It requires the host to have whatever native tooling that binary
+// depends on (anaconda/live-build, etc.) installed.
+type NativeBackend struct {
+	BinaryPath string
+}
+
+func (n *NativeBackend) Run(command, arg string, jsonOutput bool) (io.ReadCloser, func() error, error) {
+	args := []string{command}
+	if arg != "" {
+		args = append(args, arg)
+	}
+	if jsonOutput {
+		args = append(args, "--json-output")
+	}
+	args = append(args, "Config.toml")
+
+	cmd := exec.Command(n.BinaryPath, args...)
+	cmd.Stderr = os.Stderr
+
+	var stdout io.ReadCloser
+	if jsonOutput {
+		var err error
+		stdout, err = cmd.StdoutPipe()
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		cmd.Stdout = os.Stdout
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	return stdout, cmd.Wait, nil
+}
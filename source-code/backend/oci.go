@@ -0,0 +1,90 @@
+package backend
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// pinnedImages maps a distro name to the digest-pinned OCI image that
+// contains a matching `ulb-backend` and its native build tooling. Pinning
+// by digest (rather than a floating tag) is what makes OCI-mode builds
+// reproducible across hosts and over time. No images are published yet,
+// so this starts empty rather than holding placeholder digests that
+// could never resolve; entries are added here once a real builder image
+// has actually been built and pushed for that distro.
+var pinnedImages = map[string]string{}
+
+// ImageForDistro returns the pinned builder image for distro, or a clear
+// error if OCI mode has no image registered for it yet.
+func ImageForDistro(distro string) (string, error) {
+	img, ok := pinnedImages[distro]
+	if !ok {
+		return "", fmt.Errorf("OCI mode not yet available for %q: no pinned builder image registered", distro)
+	}
+	return img, nil
+}
+
+// OCIBackend runs build/clean/status inside a rootless Podman container
+// using a pinned image for the target distro, so the host never needs
+// anaconda, live-build, or any other native build tooling installed.
+type OCIBackend struct {
+	// Image is the pinned, digest-referenced builder image to run.
+	Image string
+	// ProjectDir is mounted read-write into the container's working
+	// directory.
+	ProjectDir string
+	// CacheDir is mounted into the container and shared across builds so
+	// DNF/apt package downloads aren't repeated from a cold container
+	// every time.
+	CacheDir string
+	// PodmanPath overrides the podman binary to invoke; defaults to
+	// "podman" on PATH.
+	PodmanPath string
+}
+
+const containerWorkdir = "/workspace"
+
+func (o *OCIBackend) Run(command, arg string, jsonOutput bool) (io.ReadCloser, func() error, error) {
+	podman := o.PodmanPath
+	if podman == "" {
+		podman = "podman"
+	}
+
+	args := []string{
+		"run", "--rm",
+		"--userns=keep-id", // map the invoking user's uid/gid so outputs keep their ownership
+		"-v", o.ProjectDir + ":" + containerWorkdir + ":Z",
+		"-v", o.CacheDir + ":/var/cache/ulb:Z",
+		"-w", containerWorkdir,
+		o.Image,
+		"ulb-backend", command,
+	}
+	if arg != "" {
+		args = append(args, arg)
+	}
+	if jsonOutput {
+		args = append(args, "--json-output")
+	}
+	args = append(args, "Config.toml")
+
+	cmd := exec.Command(podman, args...)
+	cmd.Stderr = os.Stderr
+
+	var stdout io.ReadCloser
+	if jsonOutput {
+		var err error
+		stdout, err = cmd.StdoutPipe()
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		cmd.Stdout = os.Stdout
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("start podman: %w", err)
+	}
+	return stdout, cmd.Wait, nil
+}
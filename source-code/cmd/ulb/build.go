@@ -0,0 +1,435 @@
+package ulb
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/backend"
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/distro"
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/events"
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/recipe"
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/tui"
+)
+
+// buildLogFormats are the valid values for `ulb build --log-format`.
+var buildLogFormats = map[string]bool{"text": true, "json": true, "tui": true}
+
+// lastBuildEventLog is where the full event stream of the most recent
+// build is persisted, for `ulb status` to replay.
+const lastBuildEventLog = "last-build.jsonl"
+
+// recipeCandidates are the recipe file names checked, in order, by a
+// project that wants computed package lists instead of flat files.
+var recipeCandidates = []string{"Recipe.sh", "ulb.sh"}
+
+func (c *commands) buildCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "build",
+		Short: "Build the ISO image",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			release, _ := cmd.Flags().GetBool("release")
+			arg := ""
+			if release {
+				arg = "--release"
+			}
+			genSBOM, _ := cmd.Flags().GetBool("sbom")
+			if genSBOM {
+				return c.runBuildWithSBOM(cmd, arg)
+			}
+			return c.runBackendWithProgress(cmd, "build", arg)
+		},
+	}
+}
+
+func (c *commands) cleanCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clean",
+		Short: "Clean the build cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.runBackend(cmd, "clean", "", false)
+		},
+	}
+}
+
+func (c *commands) sbomCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sbom",
+		Short: "Generate an SBOM and provenance attestation for the built ISO",
+		Long: `sbom builds the project (so it can observe the backend's
+"rootfs" artifact event) and then produces an SPDX-JSON and a CycloneDX-JSON
+software bill of materials, plus an in-toto provenance attestation, next
+to the ISO in build/release/. Set ULB_SBOM_SIGNING_KEY to the path of a
+minisign secret key file to sign the attestation with the same key
+infrastructure as "ulb update"; without it the attestation is unsigned.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.runBuildWithSBOM(cmd, "")
+		},
+	}
+}
+
+// newBackend selects the BackendRunner to run build/clean/status against:
+// deps.BackendRunner if the caller (a test) set one, otherwise the
+// --backend flag, otherwise `backend = "..."` in Config.toml, defaulting
+// to the native downloaded binary.
+func (c *commands) newBackend(cmd *cobra.Command) (BackendRunner, error) {
+	if c.deps.BackendRunner != nil {
+		return c.deps.BackendRunner, nil
+	}
+
+	name := viper.GetString("backend")
+	if flagVal, _ := cmd.Flags().GetString("backend"); flagVal != "" {
+		name = flagVal
+	}
+	if name == "" {
+		name = string(backend.Native)
+	}
+
+	switch backend.Name(name) {
+	case backend.Native:
+		return &backend.NativeBackend{BinaryPath: c.deps.BackendPath}, nil
+	case backend.OCI:
+		image, err := backend.ImageForDistro(viper.GetString("distro"))
+		if err != nil {
+			return nil, err
+		}
+		projectDir, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("resolve project directory: %w", err)
+		}
+		cacheDir := filepath.Join(filepath.Dir(c.deps.BackendPath), "cache", viper.GetString("distro"))
+		if err := c.deps.Filesystem.MkdirAll(cacheDir, 0755); err != nil {
+			return nil, fmt.Errorf("create package cache dir: %w", err)
+		}
+		return &backend.OCIBackend{Image: image, ProjectDir: projectDir, CacheDir: cacheDir}, nil
+	case backend.Distro:
+		db, err := distro.Lookup(viper.GetString("distro"))
+		if err != nil {
+			return nil, err
+		}
+		packages, err := readPackageList(c.deps.Filesystem, "package-lists")
+		if err != nil {
+			return nil, fmt.Errorf("read package-lists: %w", err)
+		}
+		packagesRemove, err := readPackageList(c.deps.Filesystem, "packages-lists-remove")
+		if err != nil {
+			return nil, fmt.Errorf("read packages-lists-remove: %w", err)
+		}
+		return &backend.DistroBackend{
+			Distro:         db,
+			ImageName:      viper.GetString("image_name"),
+			Installer:      viper.GetString("installer"),
+			Architecture:   viper.GetString("architecture"),
+			Packages:       packages,
+			PackagesRemove: packagesRemove,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q: expected %q, %q or %q", name, backend.Native, backend.OCI, backend.Distro)
+	}
+}
+
+// readPackageList reads a flat package-lists-style file (one package per
+// line, "#" comments and blank lines ignored), the same format `ulb init`
+// scaffolds and materializeRecipe writes. A missing file yields an empty
+// list rather than an error, since package removal lists in particular
+// are often left absent.
+func readPackageList(fs afero.Fs, path string) ([]string, error) {
+	exists, err := afero.Exists(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+	raw, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, line := range strings.Split(string(raw), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return out, nil
+}
+
+// findRecipe returns the path of the recipe file in the current project,
+// if any, checking recipeCandidates in order.
+func (c *commands) findRecipe() string {
+	for _, name := range recipeCandidates {
+		if exists, _ := afero.Exists(c.deps.Filesystem, name); exists {
+			return name
+		}
+	}
+	return ""
+}
+
+// materializeRecipe evaluates a Recipe.sh/ulb.sh in the current project,
+// if one exists, and writes its resulting package/repo lists and hook
+// scripts into the same flat files and directories `ulb init` scaffolds,
+// so the backend doesn't need to know recipes exist at all.
+func (c *commands) materializeRecipe(buildDir string) error {
+	path := c.findRecipe()
+	if path == "" {
+		return nil
+	}
+	// recipe.Load reads the recipe file directly off disk, so this only
+	// works against a real project directory, not an in-memory fs.
+	r, err := recipe.Load(path, buildDir)
+	if err != nil {
+		return fmt.Errorf("evaluate %s: %w", path, err)
+	}
+
+	fs := c.deps.Filesystem
+	if err := afero.WriteFile(fs, "package-lists", []byte(strings.Join(r.Packages, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("write package-lists: %w", err)
+	}
+	if err := afero.WriteFile(fs, "packages-lists-remove", []byte(strings.Join(r.PackagesRemove, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("write packages-lists-remove: %w", err)
+	}
+	if err := fs.MkdirAll("repos", 0755); err != nil {
+		return err
+	}
+	if err := afero.WriteFile(fs, filepath.Join("repos", "recipe-repos.list"), []byte(strings.Join(r.Repos, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("write repos/recipe-repos.list: %w", err)
+	}
+	if err := fs.MkdirAll("scripts", 0755); err != nil {
+		return err
+	}
+	if r.CustomizeRootScript != "" {
+		if err := afero.WriteFile(fs, filepath.Join("scripts", "customize_root.sh"), []byte(r.CustomizeRootScript), 0755); err != nil {
+			return fmt.Errorf("write scripts/customize_root.sh: %w", err)
+		}
+	}
+	if r.FinalizeISOScript != "" {
+		if err := afero.WriteFile(fs, filepath.Join("scripts", "finalize_iso.sh"), []byte(r.FinalizeISOScript), 0755); err != nil {
+			return fmt.Errorf("write scripts/finalize_iso.sh: %w", err)
+		}
+	}
+
+	if r.Distro != "" {
+		viper.Set("distro", r.Distro)
+	}
+	if r.ImageName != "" {
+		viper.Set("image_name", r.ImageName)
+	}
+	return nil
+}
+
+func (c *commands) runBackend(cmd *cobra.Command, command string, arg string, jsonOutput bool) error {
+	if err := c.materializeRecipe("build"); err != nil {
+		return err
+	}
+	if err := c.validateConfig(); err != nil {
+		return err
+	}
+	b, err := c.newBackend(cmd)
+	if err != nil {
+		return err
+	}
+	stdout, wait, err := b.Run(command, arg, jsonOutput)
+	if err != nil {
+		return err
+	}
+	if stdout != nil {
+		defer stdout.Close()
+		io.Copy(c.deps.Stdout, stdout)
+	}
+	return wait()
+}
+
+// runBackendWithProgress runs command against the backend's --json-output
+// event stream, rendering it as logFormat (text, json, or a live TUI), and
+// persists the whole stream to build/.cache/last-build.jsonl along the
+// way so `ulb status` can replay it later.
+func (c *commands) runBackendWithProgress(cmd *cobra.Command, command string, arg string) error {
+	if err := c.materializeRecipe("build"); err != nil {
+		return err
+	}
+	if err := c.validateConfig(); err != nil {
+		return err
+	}
+	logFormat, _ := cmd.Flags().GetString("log-format")
+	if !buildLogFormats[logFormat] {
+		return fmt.Errorf("unknown --log-format %q: expected text, json or tui", logFormat)
+	}
+
+	b, err := c.newBackend(cmd)
+	if err != nil {
+		return err
+	}
+	stdout, wait, err := b.Run(command, arg, true)
+	if err != nil {
+		return err
+	}
+	defer stdout.Close()
+
+	recorder, err := c.newBuildRecorder()
+	if err != nil {
+		return err
+	}
+	defer recorder.Close()
+
+	ch := make(chan events.Event)
+	go streamEvents(stdout, ch)
+
+	switch logFormat {
+	case "json":
+		for evt := range ch {
+			recorder.Record(evt)
+			data, _ := json.Marshal(evt)
+			fmt.Fprintln(c.deps.Stdout, string(data))
+		}
+	case "text":
+		for evt := range ch {
+			recorder.Record(evt)
+			c.printText(evt)
+		}
+	case "tui":
+		model := tui.New(ch, func(evt events.Event) { recorder.Record(evt) })
+		if _, err := tea.NewProgram(model).Run(); err != nil {
+			return err
+		}
+	}
+
+	return wait()
+}
+
+// runBuildWithSBOM runs a build while watching the backend's event stream
+// for a "rootfs" artifact event, then generates an SBOM and provenance
+// attestation from the rootfs it points at.
+func (c *commands) runBuildWithSBOM(cmd *cobra.Command, arg string) error {
+	if err := c.materializeRecipe("build"); err != nil {
+		return err
+	}
+	if err := c.validateConfig(); err != nil {
+		return err
+	}
+	b, err := c.newBackend(cmd)
+	if err != nil {
+		return err
+	}
+	stdout, wait, err := b.Run("build", arg, true)
+	if err != nil {
+		return err
+	}
+	defer stdout.Close()
+
+	recorder, err := c.newBuildRecorder()
+	if err != nil {
+		return err
+	}
+	defer recorder.Close()
+
+	ch := make(chan events.Event)
+	go streamEvents(stdout, ch)
+
+	var rootfsPath string
+	for evt := range ch {
+		recorder.Record(evt)
+		if evt.Type == events.TypeArtifact && evt.Artifact == "rootfs" {
+			rootfsPath = evt.Path
+			continue
+		}
+		c.printText(evt)
+	}
+	if err := wait(); err != nil {
+		return fmt.Errorf("build: %w", err)
+	}
+	if rootfsPath == "" {
+		return fmt.Errorf("backend did not emit a rootfs artifact event; cannot generate an SBOM")
+	}
+
+	return c.generateSBOM(rootfsPath)
+}
+
+// newBuildRecorder opens the event log the current build's stream is
+// persisted to.
+func (c *commands) newBuildRecorder() (*events.Recorder, error) {
+	if err := c.deps.Filesystem.MkdirAll(filepath.Join("build", ".cache"), 0755); err != nil {
+		return nil, err
+	}
+	return events.NewRecorder(filepath.Join("build", ".cache", lastBuildEventLog))
+}
+
+// streamEvents decodes newline-delimited events from r and sends each to
+// ch, closing ch once r is exhausted. A line that isn't a valid Event is
+// forwarded as a plain log line rather than dropped.
+func streamEvents(r io.Reader, ch chan<- events.Event) {
+	defer close(ch)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		evt, err := events.Decode(line)
+		if err != nil {
+			evt = events.Event{V: events.Version, Type: events.TypeLog, Severity: events.SeverityInfo, Message: string(line)}
+		}
+		ch <- evt
+	}
+}
+
+// printText renders one event as a single human-readable line, for
+// --log-format=text.
+func (c *commands) printText(evt events.Event) {
+	switch evt.Type {
+	case events.TypeStageBegin:
+		fmt.Fprintf(c.deps.Stdout, "==> %s\n", evt.Stage)
+	case events.TypeStageEnd:
+		fmt.Fprintf(c.deps.Stdout, "<== %s\n", evt.Stage)
+	case events.TypeProgress:
+		fmt.Fprintf(c.deps.Stdout, "    %s: %.0f%%\n", evt.ID, evt.Progress*100)
+	case events.TypeLog:
+		fmt.Fprintln(c.deps.Stdout, evt.Message)
+	case events.TypeWarning:
+		fmt.Fprintln(c.deps.Stdout, "warn:", evt.Message)
+	case events.TypeError:
+		fmt.Fprintln(c.deps.Stdout, "error:", evt.Message)
+	case events.TypeArtifact:
+		fmt.Fprintf(c.deps.Stdout, "artifact: %s -> %s\n", evt.Artifact, evt.Path)
+	}
+}
+
+func (c *commands) validateConfig() error {
+	viper.SetConfigName("Config")
+	viper.SetConfigType("toml")
+	viper.AddConfigPath(".")
+	if err := viper.ReadInConfig(); err != nil {
+		// A recipe supplies distro/image_name itself, so a project built
+		// entirely around Recipe.sh/ulb.sh is allowed to skip Config.toml.
+		if _, notFound := err.(viper.ConfigFileNotFoundError); !notFound || c.findRecipe() == "" {
+			return err
+		}
+	}
+	// Validate required fields
+	distroName := viper.GetString("distro")
+	if distroName == "" {
+		return fmt.Errorf("distro is required")
+	}
+	if viper.GetString("image_name") == "" {
+		return fmt.Errorf("image_name is required")
+	}
+
+	// Delegate the rest to the distro's own backend, e.g. rejecting
+	// installer = "anaconda" under distro = "debian".
+	b, err := distro.Lookup(distroName)
+	if err != nil {
+		return err
+	}
+	if err := b.ValidateInstaller(viper.GetString("installer")); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package ulb
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+)
+
+// withProject chdirs into a fresh temp directory containing a minimal
+// Config.toml, restoring the previous directory and resetting viper's
+// global config state (validateConfig reads through viper, which is a
+// process-wide singleton) once the test ends.
+func withProject(t *testing.T, configToml string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Config.toml"), []byte(configToml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	prev, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(prev)
+		viper.Reset()
+	})
+}
+
+func newTestDeps(backend BackendRunner, stdout, stderr *bytes.Buffer) Dependencies {
+	return Dependencies{
+		Filesystem:    afero.NewOsFs(),
+		BackendRunner: backend,
+		HomeDir:       "/nonexistent-home",
+		BackendPath:   "/nonexistent-home/.ulb/backend",
+		Stdout:        stdout,
+		Stderr:        stderr,
+		Clock:         fixedClock{},
+	}
+}
+
+func TestBuildLogFormats(t *testing.T) {
+	tests := []struct {
+		name      string
+		logFormat string
+		stdout    string
+		wantSub   string
+	}{
+		{
+			name:      "json passthrough",
+			logFormat: "json",
+			stdout:    `{"v":1,"type":"artifact","artifact":"rootfs","path":"/build/rootfs"}` + "\n",
+			wantSub:   `"artifact":"rootfs"`,
+		},
+		{
+			name:      "text rendering",
+			logFormat: "text",
+			stdout:    `{"v":1,"type":"stage_begin","id":"bootstrap","stage":"Bootstrap rootfs"}` + "\n",
+			wantSub:   "==> Bootstrap rootfs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withProject(t, "distro = \"fedora\"\nimage_name = \"test-iso\"\n")
+
+			var stdout, stderr bytes.Buffer
+			fb := &fakeBackend{stdout: tt.stdout}
+			root := New(newTestDeps(fb, &stdout, &stderr))
+			root.SetArgs([]string{"build", "--log-format", tt.logFormat})
+
+			if err := root.Execute(); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if !strings.Contains(stdout.String(), tt.wantSub) {
+				t.Errorf("stdout = %q, want substring %q", stdout.String(), tt.wantSub)
+			}
+			if len(fb.calls) != 1 || fb.calls[0].command != "build" {
+				t.Errorf("backend calls = %+v, want one build call", fb.calls)
+			}
+		})
+	}
+}
+
+func TestBuildRejectsUnknownLogFormat(t *testing.T) {
+	withProject(t, "distro = \"fedora\"\nimage_name = \"test-iso\"\n")
+
+	var stdout, stderr bytes.Buffer
+	fb := &fakeBackend{}
+	root := New(newTestDeps(fb, &stdout, &stderr))
+	root.SetArgs([]string{"build", "--log-format", "xml"})
+
+	if err := root.Execute(); err == nil {
+		t.Fatal("expected an error for an unknown --log-format")
+	}
+}
+
+func TestBuildRejectsMismatchedInstaller(t *testing.T) {
+	withProject(t, "distro = \"debian\"\nimage_name = \"test-iso\"\ninstaller = \"anaconda\"\n")
+
+	var stdout, stderr bytes.Buffer
+	fb := &fakeBackend{}
+	root := New(newTestDeps(fb, &stdout, &stderr))
+	root.SetArgs([]string{"build"})
+
+	if err := root.Execute(); err == nil {
+		t.Fatal("expected an error for installer = anaconda under distro = debian")
+	}
+	if len(fb.calls) != 0 {
+		t.Errorf("backend should not have been invoked, got calls %+v", fb.calls)
+	}
+}
@@ -0,0 +1,105 @@
+// Package ulb builds the `ulb` cobra command tree against an injected
+// Dependencies, instead of the package-level globals and init() side
+// effects the CLI used to be wired up with. That makes every subcommand
+// testable with fakes (an in-memory filesystem, a scripted backend, a
+// roundtripper HTTP client) and lets ulb be embedded as a library by
+// anything that wants its own main().
+package ulb
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// Version is the release version baked into this build. It is compared
+// against release manifests to refuse downgrades.
+const Version = "0.1.0"
+
+// BackendRunner is the shape a command needs from a build backend:
+// exactly backend.Backend's Run method, redeclared here so a test can
+// supply a scripted fake without importing backend's exec-based
+// implementations.
+type BackendRunner interface {
+	Run(command, arg string, jsonOutput bool) (stdout io.ReadCloser, wait func() error, err error)
+}
+
+// Clock is injected wherever a command needs the current time, so tests
+// can pin it instead of racing wall-clock time.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is the production Clock, backed by time.Now.
+type RealClock struct{}
+
+// Now implements Clock.
+func (RealClock) Now() time.Time { return time.Now() }
+
+// Dependencies are everything a command needs from its environment. A
+// production main() builds one with real implementations; tests build one
+// from fakes.
+type Dependencies struct {
+	// HTTPClient is used by `ulb update` to fetch release manifests and
+	// artifacts.
+	HTTPClient *http.Client
+	// Filesystem backs every directory/file operation a command performs
+	// directly (scaffolding Config.toml, writing the SBOM, creating
+	// build/.cache). Packages this CLI calls into (recipe, update,
+	// sbom, events) still do their own real os I/O; faking their inputs
+	// in a test means giving them paths under a real temp directory.
+	Filesystem afero.Fs
+	// BackendRunner, if set, is used for every build/clean/status
+	// invocation instead of selecting backend.Native/backend.OCI/
+	// backend.Distro from Config.toml. Production leaves it nil; tests
+	// set it to a scripted fake.
+	BackendRunner BackendRunner
+	// HomeDir is the user's home directory, used to resolve ~/.ulb.
+	HomeDir string
+	// BackendPath is where the native backend binary is installed.
+	BackendPath string
+	Stdout      io.Writer
+	Stderr      io.Writer
+	Clock       Clock
+}
+
+// commands closes every subcommand's RunE over a single Dependencies, so
+// nothing but this struct carries the state that used to live in package
+// globals.
+type commands struct {
+	deps Dependencies
+}
+
+// New builds the ulb root command wired to deps. main() calls this once
+// with production dependencies and Execute()s the result.
+func New(deps Dependencies) *cobra.Command {
+	c := &commands{deps: deps}
+
+	root := &cobra.Command{
+		Use:   "ulb",
+		Short: "Universal Live Builder - Tool for building custom live ISOs",
+		Long:  `ULB is a versatile tool that allows users to build customized live ISO images for various Linux distributions, including Fedora, Debian, Arch, Alpine and openSUSE. It uses containerization for reproducible builds.`,
+	}
+	root.SetOut(deps.Stdout)
+	root.SetErr(deps.Stderr)
+	root.PersistentFlags().String("backend", "", "Backend execution mode: native, oci or distro (overrides Config.toml)")
+
+	build := c.buildCmd()
+	build.Flags().BoolP("release", "r", false, "Build release ISO")
+	build.Flags().Bool("sbom", false, "Generate an SBOM and provenance attestation after building")
+	build.Flags().String("log-format", "tui", "Build output format: text, json or tui")
+
+	initialize := c.initCmd()
+	initialize.Flags().Bool("recipe", false, "Scaffold a Recipe.sh build recipe instead of flat package-lists files")
+	initialize.Flags().String("distro", "fedora", "Base distribution to scaffold Config.toml and package-lists for (fedora, debian, arch, alpine, opensuse)")
+
+	update := c.updateCmd()
+	update.Flags().Bool("check", false, "Check whether an update is available without installing it")
+	update.Flags().String("channel", "stable", "Release channel to update from (stable or beta)")
+
+	root.AddCommand(c.cleanCmd(), build, initialize, c.docsCmd(), update, c.statusCmd(), c.sbomCmd())
+	return root
+}
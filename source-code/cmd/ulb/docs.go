@@ -0,0 +1,70 @@
+package ulb
+
+import (
+	"embed"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+//go:embed docs.md
+var docsContent embed.FS
+
+func (c *commands) docsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "docs",
+		Short: "Display documentation in TUI",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content, err := docsContent.ReadFile("docs.md")
+			if err != nil {
+				return err
+			}
+			p := tea.NewProgram(initialViewportModel(string(content)))
+			if _, err := p.Run(); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+// viewportModel is the bubbletea model behind `ulb docs`.
+type viewportModel struct {
+	viewport viewport.Model
+	content  string
+}
+
+func initialViewportModel(content string) viewportModel {
+	vp := viewport.New(78, 20)
+	vp.SetContent(content)
+	return viewportModel{viewport: vp, content: content}
+}
+
+func (m viewportModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m viewportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc":
+			return m, tea.Quit
+		case "up":
+			m.viewport.LineUp(1)
+		case "down":
+			m.viewport.LineDown(1)
+		}
+	case tea.WindowSizeMsg:
+		m.viewport.Width = msg.Width
+		m.viewport.Height = msg.Height
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m viewportModel) View() string {
+	return m.viewport.View()
+}
@@ -0,0 +1,53 @@
+package ulb
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// fakeBackend is a scripted BackendRunner: it replays a fixed stdout
+// stream (one --json-output event per line) and returns a fixed error
+// from wait, regardless of which command/arg it was invoked with.
+type fakeBackend struct {
+	calls   []call
+	stdout  string
+	waitErr error
+}
+
+type call struct {
+	command string
+	arg     string
+	json    bool
+}
+
+func (f *fakeBackend) Run(command, arg string, jsonOutput bool) (io.ReadCloser, func() error, error) {
+	f.calls = append(f.calls, call{command, arg, jsonOutput})
+	return io.NopCloser(strings.NewReader(f.stdout)), func() error { return f.waitErr }, nil
+}
+
+// roundTripFunc adapts a function to http.RoundTripper, so a test can
+// script canned HTTP responses without a real listener.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func newFakeHTTPClient(fn roundTripFunc) *http.Client {
+	return &http.Client{Transport: fn}
+}
+
+func stubResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+// fixedClock is a Clock pinned to a constant instant, for deterministic
+// timestamps in generated output.
+type fixedClock struct{ t time.Time }
+
+func (f fixedClock) Now() time.Time { return f.t }
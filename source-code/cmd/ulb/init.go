@@ -0,0 +1,73 @@
+package ulb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/distro"
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/recipe"
+)
+
+func (c *commands) initCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Initialize a new project skeleton",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := c.deps.Filesystem
+
+			// Create directories
+			dirs := []string{"files", "install-files", "scripts", "repos", "build/release", "build/.cache"}
+			for _, d := range dirs {
+				fs.MkdirAll(d, 0755)
+			}
+
+			useRecipe, _ := cmd.Flags().GetBool("recipe")
+			if useRecipe {
+				if err := afero.WriteFile(fs, "Recipe.sh", []byte(recipe.Skeleton), 0755); err != nil {
+					return err
+				}
+				fmt.Fprintln(c.deps.Stdout, "Project initialized with Recipe.sh")
+				return nil
+			}
+
+			distroName, _ := cmd.Flags().GetString("distro")
+			b, err := distro.Lookup(distroName)
+			if err != nil {
+				return err
+			}
+
+			// Create a Config.toml scaffolded with this distro's own defaults.
+			configContent := fmt.Sprintf(`
+# ULB Configuration File
+# distro: The base distribution (%s)
+# image_name: Name of the output ISO
+# installer: Optional installer package (defaults to %q for this distro)
+# architecture: Optional architecture (defaults to %q for this distro)
+# backend: Optional execution backend, "native" (default), "oci", or
+#   "distro" (drives this distro's Backend directly, no ulb-backend binary)
+
+distro = %q
+image_name = "my-live-iso"
+installer = %q # Optional
+architecture = %q # Optional
+backend = "native" # Optional
+`, strings.Join(distro.Names(), ", "), b.DefaultInstaller(), b.SupportedArches()[0], distroName, b.DefaultInstaller(), b.SupportedArches()[0])
+			afero.WriteFile(fs, "Config.toml", []byte(configContent), 0644)
+			// Example package-lists file, seeded with this distro's minimal
+			// bootable package set.
+			packageListsContent := "# Package Lists\n# One package per line\n" + strings.Join(b.DefaultPackages(), "\n") + "\n"
+			afero.WriteFile(fs, "package-lists", []byte(packageListsContent), 0644)
+			// Example packages-lists-remove file
+			removeListsContent := `# Packages to Remove
+# One package per line
+# example-package
+`
+			afero.WriteFile(fs, "packages-lists-remove", []byte(removeListsContent), 0644)
+			fmt.Fprintf(c.deps.Stdout, "Project initialized for %s\n", distroName)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package ulb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestInitScaffoldsPerDistro(t *testing.T) {
+	tests := []struct {
+		distro        string
+		wantInstaller string
+		wantPackage   string
+	}{
+		{distro: "fedora", wantInstaller: "anaconda", wantPackage: "kernel"},
+		{distro: "debian", wantInstaller: "live-build", wantPackage: "live-boot"},
+		{distro: "arch", wantInstaller: "archiso", wantPackage: "base"},
+		{distro: "alpine", wantInstaller: "mkimage.sh", wantPackage: "alpine-base"},
+		{distro: "opensuse", wantInstaller: "kiwi", wantPackage: "kernel-default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.distro, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			var stdout, stderr bytes.Buffer
+			root := New(Dependencies{
+				Filesystem: fs,
+				Stdout:     &stdout,
+				Stderr:     &stderr,
+				Clock:      fixedClock{},
+			})
+			root.SetArgs([]string{"init", "--distro", tt.distro})
+
+			if err := root.Execute(); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+
+			config, err := afero.ReadFile(fs, "Config.toml")
+			if err != nil {
+				t.Fatalf("read Config.toml: %v", err)
+			}
+			if !strings.Contains(string(config), `distro = "`+tt.distro+`"`) {
+				t.Errorf("Config.toml = %q, want distro %q", config, tt.distro)
+			}
+			if !strings.Contains(string(config), tt.wantInstaller) {
+				t.Errorf("Config.toml = %q, want installer %q", config, tt.wantInstaller)
+			}
+
+			packageLists, err := afero.ReadFile(fs, "package-lists")
+			if err != nil {
+				t.Fatalf("read package-lists: %v", err)
+			}
+			if !strings.Contains(string(packageLists), tt.wantPackage) {
+				t.Errorf("package-lists = %q, want package %q", packageLists, tt.wantPackage)
+			}
+		})
+	}
+}
+
+func TestInitRecipeSkipsConfigToml(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	var stdout, stderr bytes.Buffer
+	root := New(Dependencies{Filesystem: fs, Stdout: &stdout, Stderr: &stderr, Clock: fixedClock{}})
+	root.SetArgs([]string{"init", "--recipe"})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if exists, _ := afero.Exists(fs, "Recipe.sh"); !exists {
+		t.Error("expected Recipe.sh to be written")
+	}
+	if exists, _ := afero.Exists(fs, "Config.toml"); exists {
+		t.Error("expected Config.toml not to be written in --recipe mode")
+	}
+}
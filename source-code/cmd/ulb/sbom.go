@@ -0,0 +1,107 @@
+package ulb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/sbom"
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/update"
+)
+
+// packageManagerByDistro maps a distro name to the package manager `ulb
+// sbom` uses to enumerate its installed packages.
+var packageManagerByDistro = map[string]string{
+	"fedora":   "rpm",
+	"opensuse": "rpm",
+	"debian":   "dpkg",
+	"alpine":   "apk",
+}
+
+// generateSBOM writes an SPDX document, a CycloneDX document and an
+// in-toto provenance attestation for the built ISO into build/release/,
+// based on the packages installed into rootfsPath. If the ISO itself can't
+// be read for hashing, the attestation is skipped with a warning.
+func (c *commands) generateSBOM(rootfsPath string) error {
+	distroName := viper.GetString("distro")
+	manager, ok := packageManagerByDistro[distroName]
+	if !ok {
+		return fmt.Errorf("sbom: no package manager known for distro %q", distroName)
+	}
+	pkgs, err := sbom.EnumeratePackages(rootfsPath, manager, distroName)
+	if err != nil {
+		return err
+	}
+
+	outDir := filepath.Join("build", "release")
+	if err := c.deps.Filesystem.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+	isoName := viper.GetString("image_name") + ".iso"
+	created := c.deps.Clock.Now()
+
+	configHash, err := c.hashFile("Config.toml")
+	if err != nil {
+		configHash = ""
+	}
+
+	if err := c.writeJSON(filepath.Join(outDir, isoName+".spdx.json"), sbom.BuildSPDX(isoName, created, configHash, pkgs)); err != nil {
+		return fmt.Errorf("write SPDX document: %w", err)
+	}
+	if err := c.writeJSON(filepath.Join(outDir, isoName+".cdx.json"), sbom.BuildCycloneDX(isoName, created, configHash, pkgs)); err != nil {
+		return fmt.Errorf("write CycloneDX document: %w", err)
+	}
+
+	isoHash, err := c.hashFile(filepath.Join(outDir, isoName))
+	if err != nil {
+		fmt.Fprintf(c.deps.Stderr, "warning: could not hash %s for provenance: %v\n", isoName, err)
+		fmt.Fprintf(c.deps.Stdout, "SBOM written to %s\n", outDir)
+		return nil
+	}
+	provenance := sbom.BuildProvenance(isoName, isoHash, configHash, created)
+	provenancePath := filepath.Join(outDir, isoName+".intoto.jsonl")
+	if err := c.writeJSON(provenancePath, provenance); err != nil {
+		return fmt.Errorf("write provenance attestation: %w", err)
+	}
+	if signKey := os.Getenv("ULB_SBOM_SIGNING_KEY"); signKey != "" {
+		data, err := afero.ReadFile(c.deps.Filesystem, provenancePath)
+		if err != nil {
+			return err
+		}
+		sig, err := update.Sign(data, signKey)
+		if err != nil {
+			return fmt.Errorf("sign provenance attestation: %w", err)
+		}
+		if err := afero.WriteFile(c.deps.Filesystem, provenancePath+".minisig", []byte(sig), 0644); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(c.deps.Stdout, "note: ULB_SBOM_SIGNING_KEY not set; provenance attestation written unsigned")
+	}
+
+	fmt.Fprintf(c.deps.Stdout, "SBOM written to %s\n", outDir)
+	return nil
+}
+
+func (c *commands) hashFile(path string) (string, error) {
+	data, err := afero.ReadFile(c.deps.Filesystem, path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (c *commands) writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(c.deps.Filesystem, path, data, 0644)
+}
@@ -0,0 +1,51 @@
+package ulb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/events"
+)
+
+func (c *commands) statusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show status of configuration and backend",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.runBackend(cmd, "status", "", false); err != nil {
+				return err
+			}
+			return c.replayLastBuild()
+		},
+	}
+}
+
+// replayLastBuild prints a short summary of the most recently persisted
+// build event stream, if one exists.
+func (c *commands) replayLastBuild() error {
+	evts, err := events.ReadAll(filepath.Join("build", ".cache", lastBuildEventLog))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if len(evts) == 0 {
+		return nil
+	}
+	fmt.Fprintln(c.deps.Stdout, "\nLast build:")
+	for _, evt := range evts {
+		switch evt.Type {
+		case events.TypeStageEnd:
+			fmt.Fprintf(c.deps.Stdout, "  %s: done\n", evt.Stage)
+		case events.TypeError:
+			fmt.Fprintf(c.deps.Stdout, "  error: %s\n", evt.Message)
+		case events.TypeArtifact:
+			fmt.Fprintf(c.deps.Stdout, "  artifact: %s -> %s\n", evt.Artifact, evt.Path)
+		}
+	}
+	return nil
+}
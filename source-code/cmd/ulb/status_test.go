@@ -0,0 +1,25 @@
+package ulb
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStatusRunsBackendAndReplaysNothingOnFirstRun(t *testing.T) {
+	withProject(t, "distro = \"fedora\"\nimage_name = \"test-iso\"\n")
+
+	var stdout, stderr bytes.Buffer
+	fb := &fakeBackend{stdout: ""}
+	root := New(newTestDeps(fb, &stdout, &stderr))
+	root.SetArgs([]string{"status"})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(fb.calls) != 1 || fb.calls[0].command != "status" {
+		t.Errorf("backend calls = %+v, want one status call", fb.calls)
+	}
+	// No build/.cache/last-build.jsonl exists yet in a fresh project, so
+	// replayLastBuild should be a silent no-op rather than an error, and
+	// Execute should have returned nil above.
+}
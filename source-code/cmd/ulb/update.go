@@ -0,0 +1,63 @@
+package ulb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/update"
+)
+
+func (c *commands) updateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "update",
+		Short: "Update the tool and backend",
+		Long: `Update fetches a signed release manifest for the selected channel,
+verifies it against a trusted minisign key (pinned into this binary, with
+overrides under ~/.ulb/trusted_keys.d), verifies the SHA256 of each
+downloaded artifact against the manifest, and only then atomically
+replaces the backend and self binaries. A failed verification leaves the
+existing binaries untouched and exits non-zero.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			checkOnly, _ := cmd.Flags().GetBool("check")
+			channel, _ := cmd.Flags().GetString("channel")
+
+			updater, err := update.NewUpdater(c.deps.HomeDir, Version, update.Channel(channel))
+			if err != nil {
+				return err
+			}
+			if c.deps.HTTPClient != nil {
+				updater.HTTPClient = c.deps.HTTPClient
+			}
+
+			manifest, err := updater.CheckManifest()
+			if err != nil {
+				if errors.Is(err, update.ErrNoUpdate) {
+					fmt.Fprintln(c.deps.Stdout, "Already up to date.")
+					return nil
+				}
+				return fmt.Errorf("check for update: %w", err)
+			}
+			if checkOnly {
+				fmt.Fprintf(c.deps.Stdout, "Update available: %s (channel %s)\n", manifest.Version, channel)
+				return nil
+			}
+
+			if err := updater.Apply(manifest, "ulb-backend", c.deps.BackendPath); err != nil {
+				return fmt.Errorf("update backend: %w", err)
+			}
+			selfPath, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("resolve own executable path: %w", err)
+			}
+			if err := updater.Apply(manifest, "ulb", selfPath); err != nil {
+				return fmt.Errorf("update self: %w", err)
+			}
+
+			fmt.Fprintf(c.deps.Stdout, "Updated to %s. Restart to apply.\n", manifest.Version)
+			return nil
+		},
+	}
+}
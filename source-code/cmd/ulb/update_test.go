@@ -0,0 +1,75 @@
+package ulb
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUpdateCheckSurfacesFetchErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   *http.Response
+		roundTrip  func(*http.Request) (*http.Response, error)
+		wantSubstr string
+	}{
+		{
+			name:       "manifest not found",
+			wantSubstr: "check for update",
+			roundTrip: func(r *http.Request) (*http.Response, error) {
+				return stubResponse(http.StatusNotFound, ""), nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			var stdout, stderr bytes.Buffer
+			root := New(Dependencies{
+				HTTPClient:  newFakeHTTPClient(tt.roundTrip),
+				Filesystem:  nil,
+				HomeDir:     home,
+				BackendPath: home + "/.ulb/backend",
+				Stdout:      &stdout,
+				Stderr:      &stderr,
+				Clock:       fixedClock{},
+			})
+			root.SetArgs([]string{"update", "--check"})
+
+			err := root.Execute()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.wantSubstr) {
+				t.Errorf("error = %q, want substring %q", err, tt.wantSubstr)
+			}
+		})
+	}
+}
+
+func TestUpdateUsesInjectedHTTPClient(t *testing.T) {
+	var requested string
+	roundTrip := func(r *http.Request) (*http.Response, error) {
+		requested = r.URL.String()
+		return stubResponse(http.StatusNotFound, ""), nil
+	}
+
+	home := t.TempDir()
+	var stdout, stderr bytes.Buffer
+	root := New(Dependencies{
+		HTTPClient:  newFakeHTTPClient(roundTrip),
+		HomeDir:     home,
+		BackendPath: home + "/.ulb/backend",
+		Stdout:      &stdout,
+		Stderr:      &stderr,
+		Clock:       fixedClock{},
+	})
+	root.SetArgs([]string{"update", "--check", "--channel", "beta"})
+	root.Execute()
+
+	if !strings.Contains(requested, "beta") {
+		t.Errorf("requested URL = %q, want it to reach the beta channel", requested)
+	}
+}
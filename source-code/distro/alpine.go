@@ -0,0 +1,57 @@
+package distro
+
+import "fmt"
+
+func init() {
+	Register("alpine", alpineBackend{})
+}
+
+// alpineBackend builds Alpine live images the way aports' mkimage.sh does:
+// apk --root installs into a fresh tree, and mkimage.sh's iso profile
+// assembles the final image from it.
+type alpineBackend struct{}
+
+func (alpineBackend) Bootstrap(cfg Config) error {
+	arch := cfg.Architecture
+	if arch == "" {
+		arch = alpineBackend{}.SupportedArches()[0]
+	}
+	return run("apk", "--root", rootfsDir, "--arch", arch, "--initdb", "add", "alpine-base")
+}
+
+func (alpineBackend) InstallPackages(pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return run("apk", append([]string{"--root", rootfsDir, "add"}, pkgs...)...)
+}
+
+func (alpineBackend) RemovePackages(pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return run("apk", append([]string{"--root", rootfsDir, "del"}, pkgs...)...)
+}
+
+func (alpineBackend) MakeISO(out string) error {
+	return run("mkimage.sh", "--tag", "edge", "--outdir", out, "--arch", "x86_64", "--profile", "iso")
+}
+
+func (alpineBackend) DefaultInstaller() string {
+	return "mkimage.sh"
+}
+
+func (alpineBackend) SupportedArches() []string {
+	return []string{"x86_64", "aarch64"}
+}
+
+func (alpineBackend) ValidateInstaller(installer string) error {
+	if installer != "" && installer != "mkimage.sh" {
+		return fmt.Errorf("distro alpine: unknown installer %q (expected %q)", installer, "mkimage.sh")
+	}
+	return nil
+}
+
+func (alpineBackend) DefaultPackages() []string {
+	return []string{"alpine-base", "linux-lts", "mkinitfs"}
+}
@@ -0,0 +1,53 @@
+package distro
+
+import "fmt"
+
+func init() {
+	Register("arch", archBackend{})
+}
+
+// archBackend builds Arch live images with archiso: pacstrap bootstraps
+// the tree with base, linux and linux-firmware, pacman installs or
+// removes packages directly into it, and mkarchiso assembles the ISO.
+type archBackend struct{}
+
+func (archBackend) Bootstrap(cfg Config) error {
+	return run("pacstrap", "-c", rootfsDir, "base", "linux", "linux-firmware")
+}
+
+func (archBackend) InstallPackages(pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return run("pacman", append([]string{"--root", rootfsDir, "-S", "--noconfirm"}, pkgs...)...)
+}
+
+func (archBackend) RemovePackages(pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return run("pacman", append([]string{"--root", rootfsDir, "-R", "--noconfirm"}, pkgs...)...)
+}
+
+func (archBackend) MakeISO(out string) error {
+	return run("mkarchiso", "-v", "-w", rootfsDir, "-o", out, ".")
+}
+
+func (archBackend) DefaultInstaller() string {
+	return "archiso"
+}
+
+func (archBackend) SupportedArches() []string {
+	return []string{"x86_64"}
+}
+
+func (archBackend) ValidateInstaller(installer string) error {
+	if installer != "" && installer != "archiso" {
+		return fmt.Errorf("distro arch: unknown installer %q (expected %q)", installer, "archiso")
+	}
+	return nil
+}
+
+func (archBackend) DefaultPackages() []string {
+	return []string{"base", "linux", "linux-firmware", "mkinitcpio-archiso"}
+}
@@ -0,0 +1,60 @@
+package distro
+
+import "fmt"
+
+func init() {
+	Register("debian", debianBackend{})
+}
+
+// debianBackend builds Debian live images with live-build: lb bootstrap
+// debootstraps the tree, lb chroot installs/removes packages inside it,
+// and lb binary assembles the final ISO.
+type debianBackend struct{}
+
+func (debianBackend) Bootstrap(cfg Config) error {
+	arch := cfg.Architecture
+	if arch == "" {
+		arch = debianBackend{}.SupportedArches()[0]
+	}
+	if err := run("lb", "config", "--architectures", arch, "--distribution", "stable"); err != nil {
+		return err
+	}
+	return run("lb", "bootstrap")
+}
+
+func (debianBackend) InstallPackages(pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return run("lb", append([]string{"chroot", rootfsDir, "apt-get", "install", "-y"}, pkgs...)...)
+}
+
+func (debianBackend) RemovePackages(pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return run("lb", append([]string{"chroot", rootfsDir, "apt-get", "remove", "-y"}, pkgs...)...)
+}
+
+func (debianBackend) MakeISO(out string) error {
+	return run("lb", "binary", "--outputdir", out)
+}
+
+func (debianBackend) DefaultInstaller() string {
+	return "live-build"
+}
+
+func (debianBackend) SupportedArches() []string {
+	return []string{"amd64", "arm64"}
+}
+
+func (debianBackend) ValidateInstaller(installer string) error {
+	if installer != "" && installer != "live-build" {
+		return fmt.Errorf("distro debian: unknown installer %q (expected %q)", installer, "live-build")
+	}
+	return nil
+}
+
+func (debianBackend) DefaultPackages() []string {
+	return []string{"live-boot", "linux-image-amd64", "systemd-sysv"}
+}
@@ -0,0 +1,107 @@
+// Package distro abstracts the distro-specific tooling that turns a
+// bootstrapped root filesystem into a live ISO: anaconda/lorax for
+// Fedora, live-build for Debian, archiso for Arch, mkimage.sh for Alpine,
+// and kiwi for openSUSE. `ulb init --distro` and validateConfig both look
+// backends up by name through Lookup, so adding a distro never requires
+// touching the frontend's command wiring.
+//
+// Third parties can add a backend without a PR to this repo: either drop
+// a Go plugin exporting a `Backend` symbol into ~/.ulb/distro-plugins, or
+// put a `ulb-distro-<name>` helper binary on PATH (see helper.go). Setting
+// `backend = "distro"` in Config.toml drives that looked-up Backend's
+// Bootstrap/InstallPackages/RemovePackages/MakeISO directly for the build
+// itself, rather than only validating config and scaffolding a project
+// (see backend.DistroBackend).
+package distro
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// rootfsDir is where Bootstrap/InstallPackages/RemovePackages/MakeISO
+// expect to find the working root filesystem, relative to the project
+// directory.
+var rootfsDir = filepath.Join("build", "rootfs")
+
+// RootfsDir returns where a Backend bootstraps and installs into, for a
+// caller (e.g. the "distro" execution backend) that drives Bootstrap/
+// InstallPackages/RemovePackages/MakeISO directly and needs to point at
+// the same rootfs a build just produced, such as for a "rootfs" artifact
+// event.
+func RootfsDir() string {
+	return rootfsDir
+}
+
+// Config carries the Config.toml fields a Backend needs to bootstrap and
+// assemble an image.
+type Config struct {
+	ImageName    string
+	Installer    string
+	Architecture string
+}
+
+// Backend drives one distro's native build tooling.
+type Backend interface {
+	// Bootstrap creates a fresh root filesystem for cfg under rootfsDir.
+	Bootstrap(cfg Config) error
+	// InstallPackages installs pkgs into the bootstrapped root.
+	InstallPackages(pkgs []string) error
+	// RemovePackages removes pkgs from the bootstrapped root.
+	RemovePackages(pkgs []string) error
+	// MakeISO assembles the finished live image at out.
+	MakeISO(out string) error
+	// DefaultInstaller is the `installer` Config.toml defaults to when a
+	// project doesn't set one explicitly.
+	DefaultInstaller() string
+	// SupportedArches lists the architectures this backend can target,
+	// most-preferred first.
+	SupportedArches() []string
+	// ValidateInstaller rejects an `installer` value this distro's
+	// tooling has no driver for (e.g. installer="anaconda" on Debian).
+	ValidateInstaller(installer string) error
+	// DefaultPackages seeds `ulb init --distro`'s package-lists with a
+	// minimal bootable package set for this distro.
+	DefaultPackages() []string
+}
+
+// registry holds the backends compiled into this binary. Built-in
+// distros register themselves from their own file's init().
+var registry = map[string]Backend{}
+
+// Register makes a Backend available under name. It is meant to be
+// called from a built-in distro's init() or from a loaded plugin's own
+// init(); it panics on a duplicate name since that can only be a
+// programming error.
+func Register(name string, b Backend) {
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("distro: backend %q already registered", name))
+	}
+	registry[name] = b
+}
+
+// Lookup resolves name to a Backend, checking built-in/plugin-registered
+// backends first and falling back to a `ulb-distro-<name>` helper binary
+// on PATH.
+func Lookup(name string) (Backend, error) {
+	if b, ok := registry[name]; ok {
+		return b, nil
+	}
+	if b, err := loadPlugin(name); err == nil {
+		return b, nil
+	}
+	if b, ok := lookupHelper(name); ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("distro: no backend registered for %q (expected a built-in distro, a plugin in ~/.ulb/distro-plugins, or ulb-distro-%s on PATH)", name, name)
+}
+
+// Names lists every distro currently registered, in no particular order,
+// e.g. for the choices `ulb init` lists in a scaffolded Config.toml.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	return names
+}
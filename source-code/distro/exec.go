@@ -0,0 +1,20 @@
+package distro
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// run invokes name with args, connecting stdout/stderr to the current
+// process so the underlying distro tooling's own progress output reaches
+// the user directly.
+func run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %v: %w", name, args, err)
+	}
+	return nil
+}
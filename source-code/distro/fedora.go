@@ -0,0 +1,58 @@
+package distro
+
+import "fmt"
+
+func init() {
+	Register("fedora", fedoraBackend{})
+}
+
+// fedoraBackend builds Fedora live images with the Fedora tooling: dnf
+// --installroot bootstraps the tree and installs/removes packages in it,
+// and lorax assembles the final ISO from it. anaconda is not invoked here.
+type fedoraBackend struct{}
+
+func (fedoraBackend) Bootstrap(cfg Config) error {
+	arch := cfg.Architecture
+	if arch == "" {
+		arch = fedoraBackend{}.SupportedArches()[0]
+	}
+	return run("dnf", "--installroot", rootfsDir, "--forcearch", arch, "--releasever", "rawhide",
+		"install", "-y", "--setopt=install_weak_deps=False", "glibc", "fedora-release")
+}
+
+func (fedoraBackend) InstallPackages(pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return run("dnf", append([]string{"--installroot", rootfsDir, "install", "-y"}, pkgs...)...)
+}
+
+func (fedoraBackend) RemovePackages(pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return run("dnf", append([]string{"--installroot", rootfsDir, "remove", "-y"}, pkgs...)...)
+}
+
+func (fedoraBackend) MakeISO(out string) error {
+	return run("lorax", "--installroot", rootfsDir, "--outputdir", out)
+}
+
+func (fedoraBackend) DefaultInstaller() string {
+	return "anaconda"
+}
+
+func (fedoraBackend) SupportedArches() []string {
+	return []string{"x86_64", "aarch64"}
+}
+
+func (fedoraBackend) ValidateInstaller(installer string) error {
+	if installer != "" && installer != "anaconda" {
+		return fmt.Errorf("distro fedora: unknown installer %q (expected %q)", installer, "anaconda")
+	}
+	return nil
+}
+
+func (fedoraBackend) DefaultPackages() []string {
+	return []string{"@core", "kernel", "grub2-efi-x64", "shim-x64"}
+}
@@ -0,0 +1,76 @@
+package distro
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// helperBackend wraps a `ulb-distro-<name>` binary found on PATH, letting
+// a third party add a distro without recompiling ulb at all: each Backend
+// method becomes one invocation of the helper with a matching
+// subcommand, e.g. `ulb-distro-void install PKG...`.
+type helperBackend struct {
+	path string
+}
+
+// lookupHelper looks for a `ulb-distro-<name>` binary on PATH.
+func lookupHelper(name string) (Backend, bool) {
+	path, err := exec.LookPath("ulb-distro-" + name)
+	if err != nil {
+		return nil, false
+	}
+	return helperBackend{path: path}, true
+}
+
+func (h helperBackend) Bootstrap(cfg Config) error {
+	return run(h.path, "bootstrap", "--installer", cfg.Installer, "--architecture", cfg.Architecture, "--image-name", cfg.ImageName)
+}
+
+func (h helperBackend) InstallPackages(pkgs []string) error {
+	return run(h.path, append([]string{"install"}, pkgs...)...)
+}
+
+func (h helperBackend) RemovePackages(pkgs []string) error {
+	return run(h.path, append([]string{"remove"}, pkgs...)...)
+}
+
+func (h helperBackend) MakeISO(out string) error {
+	return run(h.path, "make-iso", out)
+}
+
+func (h helperBackend) DefaultInstaller() string {
+	out, err := exec.Command(h.path, "default-installer").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func (h helperBackend) SupportedArches() []string {
+	out, err := exec.Command(h.path, "supported-arches").Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(out))
+}
+
+func (h helperBackend) ValidateInstaller(installer string) error {
+	cmd := exec.Command(h.path, "validate-installer", installer)
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("distro %s: installer %q rejected (exit %s)", h.path, installer, strconv.Itoa(exitErr.ExitCode()))
+		}
+		return err
+	}
+	return nil
+}
+
+func (h helperBackend) DefaultPackages() []string {
+	out, err := exec.Command(h.path, "default-packages").Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(out))
+}
@@ -0,0 +1,53 @@
+package distro
+
+import "fmt"
+
+func init() {
+	Register("opensuse", opensuseBackend{})
+}
+
+// opensuseBackend builds openSUSE live images with kiwi: `kiwi-ng system
+// prepare` bootstraps and installs into the tree, and `kiwi-ng system
+// create` assembles the final ISO from it.
+type opensuseBackend struct{}
+
+func (opensuseBackend) Bootstrap(cfg Config) error {
+	return run("kiwi-ng", "system", "prepare", "--description", ".", "--root", rootfsDir)
+}
+
+func (opensuseBackend) InstallPackages(pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return run("zypper", append([]string{"--root", rootfsDir, "install", "-y"}, pkgs...)...)
+}
+
+func (opensuseBackend) RemovePackages(pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	return run("zypper", append([]string{"--root", rootfsDir, "remove", "-y"}, pkgs...)...)
+}
+
+func (opensuseBackend) MakeISO(out string) error {
+	return run("kiwi-ng", "system", "create", "--root", rootfsDir, "--target-dir", out)
+}
+
+func (opensuseBackend) DefaultInstaller() string {
+	return "kiwi"
+}
+
+func (opensuseBackend) SupportedArches() []string {
+	return []string{"x86_64", "aarch64"}
+}
+
+func (opensuseBackend) ValidateInstaller(installer string) error {
+	if installer != "" && installer != "kiwi" {
+		return fmt.Errorf("distro opensuse: unknown installer %q (expected %q)", installer, "kiwi")
+	}
+	return nil
+}
+
+func (opensuseBackend) DefaultPackages() []string {
+	return []string{"patterns-base-base", "kernel-default", "grub2-x86_64-efi"}
+}
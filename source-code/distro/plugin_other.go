@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package distro
+
+import "fmt"
+
+// loadPlugin is a stub on platforms where Go's plugin package isn't
+// supported; third-party distros still work there via a ulb-distro-<name>
+// helper binary (see helper.go).
+func loadPlugin(name string) (Backend, error) {
+	return nil, fmt.Errorf("distro plugins are not supported on this platform")
+}
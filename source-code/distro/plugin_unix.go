@@ -0,0 +1,40 @@
+//go:build linux || darwin
+
+package distro
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+)
+
+// loadPlugin loads ~/.ulb/distro-plugins/<name>.so and returns the Backend
+// value it exports as a symbol named "Backend". Go plugins are only
+// usable with plugin.Open's own ABI constraints (same Go toolchain
+// version, same module versions as this binary), so this is best-effort:
+// a third party's plugin repo documents the toolchain it was built with.
+func loadPlugin(name string) (Backend, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(home, ".ulb", "distro-plugins", name+".so")
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open distro plugin %s: %w", path, err)
+	}
+	sym, err := p.Lookup("Backend")
+	if err != nil {
+		return nil, fmt.Errorf("distro plugin %s: %w", path, err)
+	}
+	b, ok := sym.(Backend)
+	if !ok {
+		return nil, fmt.Errorf("distro plugin %s: Backend symbol does not implement distro.Backend", path)
+	}
+	return b, nil
+}
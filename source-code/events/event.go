@@ -0,0 +1,47 @@
+// Package events defines the versioned JSON event protocol the backend
+// speaks over its --json-output stream. Unlike the old flat
+// {"stage","progress"} line, an Event carries an id and an optional
+// parent so the frontend can render concurrently-running stages as a
+// tree, plus log/warning/error/artifact events for everything that isn't
+// progress.
+package events
+
+// Type is the kind of an Event.
+type Type string
+
+const (
+	TypeStageBegin Type = "stage_begin"
+	TypeStageEnd   Type = "stage_end"
+	TypeProgress   Type = "progress"
+	TypeLog        Type = "log"
+	TypeWarning    Type = "warning"
+	TypeError      Type = "error"
+	TypeArtifact   Type = "artifact"
+)
+
+// Severity classifies a log event for coloring.
+type Severity string
+
+const (
+	SeverityInfo  Severity = "info"
+	SeverityWarn  Severity = "warn"
+	SeverityError Severity = "error"
+)
+
+// Version is the protocol version every Event on the current stream
+// carries in its V field.
+const Version = 1
+
+// Event is one line of the backend's --json-output stream.
+type Event struct {
+	V        int      `json:"v"`
+	Type     Type     `json:"type"`
+	ID       string   `json:"id,omitempty"`
+	Parent   string   `json:"parent,omitempty"`
+	Stage    string   `json:"stage,omitempty"`    // human label for stage_begin/stage_end
+	Progress float64  `json:"progress,omitempty"` // 0..1, for progress events
+	Message  string   `json:"message,omitempty"`  // for log/warning/error
+	Severity Severity `json:"severity,omitempty"`
+	Artifact string   `json:"artifact,omitempty"` // display name, for artifact events
+	Path     string   `json:"path,omitempty"`     // filesystem path, for artifact events
+}
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Decode parses one line of the --json-output stream into an Event.
+func Decode(line []byte) (Event, error) {
+	var evt Event
+	if err := json.Unmarshal(line, &evt); err != nil {
+		return Event{}, fmt.Errorf("decode event: %w", err)
+	}
+	return evt, nil
+}
+
+// Recorder persists every event it sees to a .jsonl file as it is read,
+// so `ulb status` can replay the last build after the fact.
+type Recorder struct {
+	w io.WriteCloser
+}
+
+// NewRecorder creates (or truncates) path and returns a Recorder that
+// appends one JSON line per recorded event.
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create event log %s: %w", path, err)
+	}
+	return &Recorder{w: f}, nil
+}
+
+// Record appends evt as one JSON line.
+func (r *Recorder) Record(evt Event) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, err = r.w.Write(append(data, '\n'))
+	return err
+}
+
+// Close releases the underlying file.
+func (r *Recorder) Close() error {
+	return r.w.Close()
+}
+
+// ReadAll loads every event previously persisted by a Recorder at path.
+func ReadAll(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		evt, err := Decode(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+		out = append(out, evt)
+	}
+	return out, scanner.Err()
+}
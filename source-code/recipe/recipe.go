@@ -0,0 +1,277 @@
+// Package recipe evaluates Recipe.sh (or ulb.sh) build recipes: a
+// restricted bash dialect that lets a project compute its package list,
+// repos and hooks with real conditional logic, instead of being limited to
+// flat package-lists files.
+//
+// A recipe declares plain variables (distro, arch, image_name, version)
+// and a set of well-known functions. packages(), packages_remove() and
+// repos() are expected to print one entry per line; their combined stdout
+// becomes the resulting list. prepare() runs eagerly inside the sandbox so
+// it can fetch sources into build/ or compute a version. customize_root()
+// and finalize_iso() need privileges the sandbox doesn't have (a chroot
+// and a finished ISO, respectively), so their bodies are handed to the
+// backend to execute at the right build stage instead of being run here.
+//
+// The sandbox described below is a best-effort guard against an honest
+// recipe's mistakes, not an OS-level security boundary against an
+// adversarial one: it governs the interpreter's own redirects/builtins and
+// which external commands dataFuncs is allowed to exec at all, but once a
+// permitted command (curl, git, ...) is running, it is a normal OS
+// subprocess that can do its own opens, and nothing here confines those.
+// Running recipe code you don't trust requires real process isolation
+// (user namespaces, bubblewrap, seccomp) in front of Load, which this
+// package does not provide.
+package recipe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// Recipe is the result of evaluating a build recipe.
+type Recipe struct {
+	Distro    string
+	Arch      string
+	ImageName string
+	Version   string
+
+	Packages       []string
+	PackagesRemove []string
+	Repos          []string
+
+	// CustomizeRootScript and FinalizeISOScript are the raw, unexecuted
+	// bodies of the recipe's customize_root() and finalize_iso()
+	// functions, for the backend to run at the appropriate build stage.
+	CustomizeRootScript string
+	FinalizeISOScript   string
+}
+
+// dataFuncs are evaluated eagerly in the sandbox; their stdout is parsed as
+// a newline-separated list.
+var dataFuncs = []string{"packages", "packages_remove", "repos", "prepare"}
+
+// Load parses and evaluates recipePath inside a sandbox that confines the
+// interpreter's own filesystem writes to buildDir and refuses to exec any
+// external command other than the declared network tools reaching a host
+// declared in repos() (see the package doc for what this does and does
+// not protect against).
+func Load(recipePath, buildDir string) (*Recipe, error) {
+	src, err := os.ReadFile(recipePath)
+	if err != nil {
+		return nil, fmt.Errorf("read recipe: %w", err)
+	}
+	file, err := syntax.NewParser().Parse(bytes.NewReader(src), filepath.Base(recipePath))
+	if err != nil {
+		return nil, fmt.Errorf("parse recipe: %w", err)
+	}
+
+	box := &sandbox{buildDir: buildDir}
+	runner, err := interp.New(
+		interp.Dir(filepath.Dir(recipePath)),
+		interp.StdIO(nil, io.Discard, os.Stderr),
+		interp.OpenHandler(box.openHandler),
+		interp.ExecHandler(box.execHandler),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create recipe sandbox: %w", err)
+	}
+
+	ctx := context.Background()
+	if err := runner.Run(ctx, file); err != nil {
+		return nil, fmt.Errorf("evaluate %s: %w", recipePath, err)
+	}
+
+	r := &Recipe{}
+	box.allowedRepo = func(host string) bool {
+		for _, repo := range r.Repos {
+			if hostOf(repo) != "" && hostOf(repo) == hostOf(host) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// dataFuncs runs repos() before prepare() so that, if prepare() shells
+	// out to curl/wget/git, the sandbox already knows which hosts it
+	// declared as trusted.
+	lists := make(map[string][]string, len(dataFuncs))
+	for _, name := range dataFuncs {
+		out, err := callFunc(ctx, runner, name)
+		if err != nil {
+			return nil, err
+		}
+		lists[name] = out
+		if name == "repos" {
+			r.Repos = out
+		}
+	}
+	r.Packages = lists["packages"]
+	r.PackagesRemove = lists["packages_remove"]
+
+	// Read distro/arch/image_name/version only after prepare() has run,
+	// since prepare() is documented to be able to compute them (e.g. a
+	// version derived from a fetched source tree); reading them earlier
+	// would silently miss anything prepare() itself assigns.
+	r.Distro = lookupVar(runner, "distro")
+	r.Arch = lookupVar(runner, "arch")
+	r.ImageName = lookupVar(runner, "image_name")
+	r.Version = lookupVar(runner, "version")
+
+	r.CustomizeRootScript = funcSource(file, "customize_root")
+	r.FinalizeISOScript = funcSource(file, "finalize_iso")
+
+	return r, nil
+}
+
+// hostOf extracts the host:port component of raw if it parses as a URL
+// with one, or "" otherwise. Comparing hosts (rather than doing a plain
+// substring match between a declared repo and a command's argument) is
+// what lets `repos()` declaring "https://example.com/repo/" actually
+// authorize a fetch of "https://example.com/repo/archive.tar.gz": the
+// latter is a longer string than the former, so a naive
+// strings.Contains(repo, arg) check can never match it.
+func hostOf(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Host
+}
+
+func lookupVar(r *interp.Runner, name string) string {
+	v, ok := r.Vars[name]
+	if !ok {
+		return ""
+	}
+	return v.String()
+}
+
+// callFunc invokes a declared function with stdout captured, returning its
+// output split into trimmed, non-empty lines. Functions the recipe didn't
+// define are simply skipped.
+func callFunc(ctx context.Context, r *interp.Runner, name string) ([]string, error) {
+	if _, ok := r.Funcs[name]; !ok {
+		return nil, nil
+	}
+	call, err := syntax.NewParser().Parse(strings.NewReader(name+"\n"), name)
+	if err != nil {
+		return nil, fmt.Errorf("build call to %s(): %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := interp.StdIO(nil, &buf, io.Discard)(r); err != nil {
+		return nil, fmt.Errorf("capture stdout for %s(): %w", name, err)
+	}
+	defer interp.StdIO(nil, io.Discard, os.Stderr)(r)
+
+	if err := r.Run(ctx, call); err != nil {
+		return nil, fmt.Errorf("run %s(): %w", name, err)
+	}
+
+	var out []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out, nil
+}
+
+// funcSource returns the verbatim source of the named function's body, or
+// "" if the recipe doesn't declare it.
+func funcSource(file *syntax.File, name string) string {
+	for _, stmt := range file.Stmts {
+		decl, ok := stmt.Cmd.(*syntax.FuncDecl)
+		if !ok || decl.Name.Value != name {
+			continue
+		}
+		var buf bytes.Buffer
+		syntax.NewPrinter().Print(&buf, decl.Body)
+		return buf.String()
+	}
+	return ""
+}
+
+// sandbox implements the restrictions a recipe runs under: the
+// interpreter's own writes are confined to buildDir, and the only
+// external commands it may exec at all are the networkTools, each of
+// which may only reach a host the recipe itself declared via repos().
+type sandbox struct {
+	buildDir    string
+	allowedRepo func(host string) bool
+}
+
+func (s *sandbox) openHandler(ctx context.Context, path string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_APPEND|os.O_TRUNC) != 0 {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		buildAbs, err := filepath.Abs(s.buildDir)
+		if err != nil {
+			return nil, err
+		}
+		if abs != buildAbs && !strings.HasPrefix(abs, buildAbs+string(filepath.Separator)) {
+			return nil, fmt.Errorf("recipe sandbox: write to %q outside %s is not permitted", path, s.buildDir)
+		}
+	}
+	return interp.DefaultOpenHandler()(ctx, path, flag, perm)
+}
+
+// networkTools are the only external commands dataFuncs is allowed to
+// exec at all, each gated on reaching a host declared in repos(). Once a
+// command execs, it's a plain OS subprocess free to open whatever files
+// it likes (openHandler can't see inside it), so the one thing this
+// sandbox can actually enforce is which binaries get to run in the first
+// place; everything not in this allowlist (tee, install, python, nc,
+// ssh, ...) is refused outright rather than let through unchecked.
+var networkTools = map[string]bool{"curl": true, "wget": true, "git": true}
+
+func (s *sandbox) execHandler(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	// Match on the basename so an absolute path (/usr/bin/curl) is checked
+	// against the allowlist the same way as a bare command name.
+	name := filepath.Base(args[0])
+	if !networkTools[name] {
+		return fmt.Errorf("recipe sandbox: execing %q is not permitted; only %s may run", args[0], strings.Join(networkToolNames(), ", "))
+	}
+	// Fail closed: a nil allowedRepo means repos() hasn't been evaluated
+	// yet (e.g. a network tool exec'd at the recipe's top level, before
+	// Load assigns it), not that the check doesn't apply. Letting that
+	// case through would allow an unrestricted fetch to any host simply
+	// by running outside a function.
+	if s.allowedRepo == nil {
+		return fmt.Errorf("recipe sandbox: %s cannot run before repos() has been evaluated", name)
+	}
+	allowed := false
+	for _, a := range args[1:] {
+		if s.allowedRepo(a) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("recipe sandbox: %s may only reach hosts declared in repos()", name)
+	}
+	return interp.DefaultExecHandler(30*time.Second)(ctx, args)
+}
+
+func networkToolNames() []string {
+	names := make([]string, 0, len(networkTools))
+	for name := range networkTools {
+		names = append(names, name)
+	}
+	return names
+}
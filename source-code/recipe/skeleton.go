@@ -0,0 +1,47 @@
+package recipe
+
+// Skeleton is the Recipe.sh scaffold written by `ulb init --recipe`.
+const Skeleton = `#!/usr/bin/env ulb-recipe
+# Recipe.sh - ULB build recipe, evaluated in a restricted shell sandbox.
+#
+# packages(), packages_remove() and repos() print one entry per line.
+# prepare() runs eagerly and may fetch sources into build/. customize_root()
+# runs chrooted into the target rootfs; finalize_iso() runs on the
+# finished ISO. The interpreter's own writes are confined to build/, and
+# prepare() may only exec curl/wget/git, each restricted to hosts declared
+# in repos() -- this is a guard against mistakes, not a security sandbox,
+# so don't run a Recipe.sh you don't already trust.
+
+distro="fedora"
+arch="x86_64"
+image_name="my-live-iso"
+version="0.1.0"
+
+packages() {
+  echo "base-system"
+  echo "kernel"
+  if [ "$arch" = "x86_64" ]; then
+    echo "grub2-efi-x64"
+  fi
+}
+
+packages_remove() {
+  : # nothing removed by default
+}
+
+repos() {
+  : # echo "https://download.example.com/repo/"
+}
+
+prepare() {
+  : # fetch sources into build/, compute a version, etc.
+}
+
+customize_root() {
+  : # runs chrooted into the target rootfs before it is squashed
+}
+
+finalize_iso() {
+  : # runs after the ISO is produced, e.g. to sign or relabel it
+}
+`
@@ -0,0 +1,80 @@
+package sbom
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+)
+
+// CycloneDXDocument is a minimal CycloneDX 1.5 document describing a
+// built ISO and the packages installed into it.
+type CycloneDXDocument struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     CycloneDXMetadata    `json:"metadata"`
+	Components   []CycloneDXComponent `json:"components"`
+}
+
+type CycloneDXMetadata struct {
+	Timestamp  string              `json:"timestamp"`
+	Component  CycloneDXComponent  `json:"component"`
+	Properties []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CycloneDXComponent struct {
+	Type     string                   `json:"type"`
+	Name     string                   `json:"name"`
+	Version  string                   `json:"version,omitempty"`
+	PURL     string                   `json:"purl,omitempty"`
+	Licenses []CycloneDXLicenseChoice `json:"licenses,omitempty"`
+}
+
+type CycloneDXLicenseChoice struct {
+	License CycloneDXLicense `json:"license"`
+}
+
+type CycloneDXLicense struct {
+	Name string `json:"name"`
+}
+
+// BuildCycloneDX assembles a CycloneDX document for isoName, recording
+// configHash (the built Config.toml's SHA256) as a metadata property so a
+// consumer can tie the SBOM back to the exact build that produced it.
+func BuildCycloneDX(isoName string, created time.Time, configHash string, pkgs []Package) *CycloneDXDocument {
+	doc := &CycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + deterministicUUID(isoName, created),
+		Version:      1,
+		Metadata: CycloneDXMetadata{
+			Timestamp: created.UTC().Format(time.RFC3339),
+			Component: CycloneDXComponent{Type: "file", Name: isoName},
+			Properties: []CycloneDXProperty{
+				{Name: "ulb:configHash", Value: configHash},
+			},
+		},
+	}
+	for _, p := range pkgs {
+		comp := CycloneDXComponent{Type: "library", Name: p.Name, Version: p.Version, PURL: p.PURL()}
+		if p.License != "" {
+			comp.Licenses = []CycloneDXLicenseChoice{{License: CycloneDXLicense{Name: p.License}}}
+		}
+		doc.Components = append(doc.Components, comp)
+	}
+	return doc
+}
+
+// deterministicUUID derives a UUID-shaped identifier from isoName and
+// created, so the same name and creation time always yield the same
+// serial number.
+func deterministicUUID(isoName string, created time.Time) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s-%d", isoName, created.UnixNano())))
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
@@ -0,0 +1,135 @@
+// Package sbom enumerates the packages installed into a built rootfs and
+// serializes them as SPDX and CycloneDX software bills of materials, plus
+// an in-toto provenance attestation for the finished ISO.
+package sbom
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Package is one installed package surfaced in a rootfs, normalized
+// across rpm/dpkg/apk so the SPDX and CycloneDX builders don't need to
+// know which package manager produced it.
+type Package struct {
+	Name          string
+	Version       string
+	Arch          string
+	License       string
+	SourcePackage string
+	Manager       string // "rpm", "dpkg" or "apk"
+	Distro        string
+}
+
+// PURL builds a package URL for p per the purl-spec type for its manager.
+func (p Package) PURL() string {
+	purlType := map[string]string{"rpm": "rpm", "dpkg": "deb", "apk": "apk"}[p.Manager]
+	if purlType == "" {
+		return fmt.Sprintf("pkg:generic/%s@%s", p.Name, p.Version)
+	}
+	return fmt.Sprintf("pkg:%s/%s/%s@%s?arch=%s", purlType, p.Distro, p.Name, p.Version, p.Arch)
+}
+
+// EnumeratePackages lists every package installed into rootfs by shelling
+// out to the given manager's query tool against rootfs as its root/admin
+// directory, so no chroot or privilege escalation is required.
+func EnumeratePackages(rootfs, manager, distro string) ([]Package, error) {
+	switch manager {
+	case "rpm":
+		return enumerateRPM(rootfs, distro)
+	case "dpkg":
+		return enumerateDpkg(rootfs, distro)
+	case "apk":
+		return enumerateApk(rootfs, distro)
+	default:
+		return nil, fmt.Errorf("sbom: unsupported package manager %q", manager)
+	}
+}
+
+const rpmQueryFormat = `%{NAME}\t%{VERSION}-%{RELEASE}\t%{ARCH}\t%{LICENSE}\t%{SOURCERPM}\n`
+
+func enumerateRPM(rootfs, distro string) ([]Package, error) {
+	out, err := exec.Command("rpm", "--root", rootfs, "-qa", "--qf", rpmQueryFormat).Output()
+	if err != nil {
+		return nil, fmt.Errorf("enumerate rpm packages in %s: %w", rootfs, err)
+	}
+	var pkgs []Package
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		f := strings.Split(line, "\t")
+		if len(f) != 5 {
+			continue
+		}
+		pkgs = append(pkgs, Package{
+			Name: f[0], Version: f[1], Arch: f[2], License: f[3], SourcePackage: f[4],
+			Manager: "rpm", Distro: distro,
+		})
+	}
+	return pkgs, nil
+}
+
+func enumerateDpkg(rootfs, distro string) ([]Package, error) {
+	admindir := rootfs + "/var/lib/dpkg"
+	format := `${Package}\t${Version}\t${Architecture}\t${source:Package}\n`
+	out, err := exec.Command("dpkg-query", "--admindir="+admindir, "-W", "-f", format).Output()
+	if err != nil {
+		return nil, fmt.Errorf("enumerate dpkg packages in %s: %w", rootfs, err)
+	}
+	var pkgs []Package
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		f := strings.Split(line, "\t")
+		if len(f) != 4 {
+			continue
+		}
+		source := f[3]
+		if source == "" {
+			source = f[0]
+		}
+		// dpkg-query doesn't expose license metadata directly, so License
+		// is left empty for dpkg packages.
+		pkgs = append(pkgs, Package{
+			Name: f[0], Version: f[1], Arch: f[2], SourcePackage: source,
+			Manager: "dpkg", Distro: distro,
+		})
+	}
+	return pkgs, nil
+}
+
+func enumerateApk(rootfs, distro string) ([]Package, error) {
+	out, err := exec.Command("apk", "info", "--root", rootfs, "-vv").Output()
+	if err != nil {
+		return nil, fmt.Errorf("enumerate apk packages in %s: %w", rootfs, err)
+	}
+	var pkgs []Package
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		// Each line looks like "name-version - description"; apk has no
+		// machine-friendly single-line format for installed packages.
+		nameVersion := strings.SplitN(line, " ", 2)[0]
+		name, version := splitApkNameVersion(nameVersion)
+		if name == "" || version == "" {
+			continue
+		}
+		pkgs = append(pkgs, Package{
+			Name: name, Version: version,
+			Manager: "apk", Distro: distro,
+		})
+	}
+	return pkgs, nil
+}
+
+// splitApkNameVersion splits an apk "name-version" token into its name and
+// version, e.g. "busybox-1.36.1-r5" -> ("busybox", "1.36.1-r5"). The naive
+// approach of splitting on the last "-" breaks on Alpine's "-rN" revision
+// suffix, which is itself joined with a "-". Instead, the version is taken
+// to start at the first "-"-separated component that begins with a digit
+// (apk package names never do), with everything before that joined back
+// together as the name.
+func splitApkNameVersion(nameVersion string) (name, version string) {
+	parts := strings.Split(nameVersion, "-")
+	for i, p := range parts {
+		if i > 0 && p != "" && p[0] >= '0' && p[0] <= '9' {
+			return strings.Join(parts[:i], "-"), strings.Join(parts[i:], "-")
+		}
+	}
+	return nameVersion, ""
+}
@@ -0,0 +1,47 @@
+package sbom
+
+import "time"
+
+// Provenance is a minimal in-toto v1 provenance statement for a built ISO.
+type Provenance struct {
+	Type          string              `json:"_type"`
+	PredicateType string              `json:"predicateType"`
+	Subject       []ProvenanceSubject `json:"subject"`
+	Predicate     ProvenancePredicate `json:"predicate"`
+}
+
+type ProvenanceSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type ProvenancePredicate struct {
+	BuildType string              `json:"buildType"`
+	Metadata  ProvenanceMetadata  `json:"metadata"`
+	Materials []ProvenanceSubject `json:"materials,omitempty"`
+}
+
+type ProvenanceMetadata struct {
+	FinishedOn string `json:"finishedOn"`
+	ConfigHash string `json:"ulb:configHash"`
+}
+
+// BuildProvenance assembles an in-toto provenance statement for the ISO
+// named isoName, built with the Config.toml hashed to configHash.
+func BuildProvenance(isoName, isoSHA256, configHash string, finished time.Time) *Provenance {
+	return &Provenance{
+		Type:          "https://in-toto.io/Statement/v1",
+		PredicateType: "https://slsa.dev/provenance/v1",
+		Subject: []ProvenanceSubject{{
+			Name:   isoName,
+			Digest: map[string]string{"sha256": isoSHA256},
+		}},
+		Predicate: ProvenancePredicate{
+			BuildType: "https://ulb.local/buildtypes/iso@v1",
+			Metadata: ProvenanceMetadata{
+				FinishedOn: finished.UTC().Format(time.RFC3339),
+				ConfigHash: configHash,
+			},
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package sbom
+
+import (
+	"fmt"
+	"time"
+)
+
+// SPDXDocument is a minimal SPDX 2.3 document describing a built ISO and
+// the packages installed into it.
+type SPDXDocument struct {
+	SPDXVersion       string           `json:"spdxVersion"`
+	DataLicense       string           `json:"dataLicense"`
+	SPDXID            string           `json:"SPDXID"`
+	Name              string           `json:"name"`
+	DocumentNamespace string           `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo `json:"creationInfo"`
+	Packages          []SPDXPackage    `json:"packages"`
+	Annotations       []SPDXAnnotation `json:"annotations,omitempty"`
+}
+
+// SPDXAnnotation is a free-form SPDX 2.3 annotation attached to the
+// document, used here to record the Config.toml hash the build ran from.
+type SPDXAnnotation struct {
+	Annotator      string `json:"annotator"`
+	AnnotationDate string `json:"annotationDate"`
+	AnnotationType string `json:"annotationType"`
+	Comment        string `json:"comment"`
+}
+
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type SPDXPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	ExternalRefs     []SPDXExternalRef `json:"externalRefs,omitempty"`
+}
+
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// BuildSPDX assembles an SPDX document for isoName, with the ISO itself
+// as the document's root package and pkgs as its components. configHash
+// (the built Config.toml's SHA256) is recorded as a document annotation so
+// a consumer can tie the SBOM back to the exact build that produced it,
+// mirroring the "ulb:configHash" property BuildCycloneDX records.
+func BuildSPDX(isoName string, created time.Time, configHash string, pkgs []Package) *SPDXDocument {
+	doc := &SPDXDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              isoName,
+		DocumentNamespace: fmt.Sprintf("https://ulb.local/spdx/%s-%d", isoName, created.Unix()),
+		CreationInfo: SPDXCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: ulb-sbom"},
+		},
+		Packages: []SPDXPackage{{
+			SPDXID:           "SPDXRef-iso",
+			Name:             isoName,
+			LicenseConcluded: "NOASSERTION",
+		}},
+		Annotations: []SPDXAnnotation{{
+			Annotator:      "Tool: ulb-sbom",
+			AnnotationDate: created.UTC().Format(time.RFC3339),
+			AnnotationType: "OTHER",
+			Comment:        "ulb:configHash=" + configHash,
+		}},
+	}
+	for i, p := range pkgs {
+		license := p.License
+		if license == "" {
+			license = "NOASSERTION"
+		}
+		doc.Packages = append(doc.Packages, SPDXPackage{
+			SPDXID:           fmt.Sprintf("SPDXRef-pkg-%d", i),
+			Name:             p.Name,
+			VersionInfo:      p.Version,
+			LicenseConcluded: license,
+			ExternalRefs: []SPDXExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  p.PURL(),
+			}},
+		})
+	}
+	return doc
+}
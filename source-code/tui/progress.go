@@ -0,0 +1,235 @@
+// Package tui renders the backend's structured build event stream: a tree
+// of concurrently-running stages, each with its own progress bar and an
+// ETA estimated from an EWMA of its throughput, alongside a scrollable log
+// pane and a final artifact summary.
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/events"
+)
+
+// ewmaAlpha weights how quickly the ETA estimate reacts to a stage
+// speeding up or slowing down; 0.3 favors recent throughput without
+// letting a single slow tick swing the estimate wildly.
+const ewmaAlpha = 0.3
+
+var (
+	styleWarn    = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	styleError   = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	styleInfo    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	styleStage   = lipgloss.NewStyle().Bold(true)
+	styleSummary = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true)
+)
+
+type stage struct {
+	id       string
+	parent   string
+	label    string
+	bar      progress.Model
+	percent  float64
+	started  time.Time
+	updated  time.Time
+	rate     float64 // EWMA of progress units per second
+	done     bool
+	failed   bool
+	children []string
+}
+
+func (s *stage) eta() time.Duration {
+	if s.done || s.rate <= 0 {
+		return 0
+	}
+	remaining := 1 - s.percent
+	if remaining <= 0 {
+		return 0
+	}
+	return time.Duration(remaining/s.rate) * time.Second
+}
+
+func (s *stage) observe(percent float64, now time.Time) {
+	if !s.updated.IsZero() {
+		dt := now.Sub(s.updated).Seconds()
+		if dt > 0 {
+			instant := (percent - s.percent) / dt
+			if s.rate == 0 {
+				s.rate = instant
+			} else {
+				s.rate = ewmaAlpha*instant + (1-ewmaAlpha)*s.rate
+			}
+		}
+	}
+	s.percent = percent
+	s.updated = now
+}
+
+// Model is a bubbletea model that consumes events.Event values (sent as
+// eventMsg) and renders them as a stage tree, a log pane, and a final
+// artifact summary.
+type Model struct {
+	stages   map[string]*stage
+	roots    []string
+	logs     viewport.Model
+	logLines []string
+	artifact []string
+	width    int
+	height   int
+	events   <-chan events.Event
+	recorder func(events.Event)
+	err      error
+	done     bool
+}
+
+type eventMsg events.Event
+type streamClosedMsg struct{}
+
+// New builds a Model that reads events from ch. record, if non-nil, is
+// called with every event so the caller can persist the stream (see
+// events.Recorder) independently of how it's rendered.
+func New(ch <-chan events.Event, record func(events.Event)) Model {
+	return Model{
+		stages:   make(map[string]*stage),
+		logs:     viewport.New(78, 8),
+		events:   ch,
+		recorder: record,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.waitForEvent()
+}
+
+func (m Model) waitForEvent() tea.Cmd {
+	return func() tea.Msg {
+		evt, ok := <-m.events
+		if !ok {
+			return streamClosedMsg{}
+		}
+		return eventMsg(evt)
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "q" || msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.logs.Width = msg.Width - 2
+		m.logs.Height = msg.Height/3 + 1
+		for _, s := range m.stages {
+			s.bar.Width = msg.Width - 20
+		}
+		return m, nil
+	case streamClosedMsg:
+		m.done = true
+		return m, tea.Quit
+	case eventMsg:
+		if m.recorder != nil {
+			m.recorder(events.Event(msg))
+		}
+		m.apply(events.Event(msg))
+		return m, m.waitForEvent()
+	case progress.FrameMsg:
+		var cmds []tea.Cmd
+		for _, s := range m.stages {
+			updated, cmd := s.bar.Update(msg)
+			if b, ok := updated.(progress.Model); ok {
+				s.bar = b
+			}
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+		return m, tea.Batch(cmds...)
+	}
+	return m, nil
+}
+
+func (m *Model) apply(evt events.Event) {
+	now := time.Now()
+	switch evt.Type {
+	case events.TypeStageBegin:
+		s := &stage{id: evt.ID, parent: evt.Parent, label: evt.Stage, started: now, bar: progress.New(progress.WithDefaultGradient())}
+		m.stages[evt.ID] = s
+		if evt.Parent == "" {
+			m.roots = append(m.roots, evt.ID)
+		} else if parent, ok := m.stages[evt.Parent]; ok {
+			parent.children = append(parent.children, evt.ID)
+		}
+	case events.TypeProgress:
+		if s, ok := m.stages[evt.ID]; ok {
+			s.observe(evt.Progress, now)
+		}
+	case events.TypeStageEnd:
+		if s, ok := m.stages[evt.ID]; ok {
+			s.done = true
+			s.percent = 1
+		}
+	case events.TypeLog:
+		m.appendLog(styleInfo.Render(evt.Message))
+	case events.TypeWarning:
+		m.appendLog(styleWarn.Render("warn: " + evt.Message))
+	case events.TypeError:
+		m.appendLog(styleError.Render("error: " + evt.Message))
+		if s, ok := m.stages[evt.ID]; ok {
+			s.failed = true
+		}
+	case events.TypeArtifact:
+		m.artifact = append(m.artifact, fmt.Sprintf("%s -> %s", evt.Artifact, evt.Path))
+	}
+}
+
+func (m *Model) appendLog(line string) {
+	m.logLines = append(m.logLines, line)
+	m.logs.SetContent(strings.Join(m.logLines, "\n"))
+	m.logs.GotoBottom()
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+	for _, id := range m.roots {
+		m.renderStage(&b, id, 0)
+	}
+	b.WriteString("\n")
+	b.WriteString(m.logs.View())
+	if m.done {
+		b.WriteString("\n" + styleSummary.Render("Artifacts:") + "\n")
+		for _, a := range m.artifact {
+			b.WriteString("  " + a + "\n")
+		}
+	}
+	return b.String()
+}
+
+func (m Model) renderStage(b *strings.Builder, id string, depth int) {
+	s, ok := m.stages[id]
+	if !ok {
+		return
+	}
+	indent := strings.Repeat("  ", depth)
+	status := ""
+	switch {
+	case s.failed:
+		status = styleError.Render(" failed")
+	case s.done:
+		status = " done"
+	case s.eta() > 0:
+		status = fmt.Sprintf(" (eta %s)", s.eta().Round(time.Second))
+	}
+	fmt.Fprintf(b, "%s%s %s%s\n", indent, styleStage.Render(s.label), s.bar.ViewAs(s.percent), status)
+	for _, child := range s.children {
+		m.renderStage(b, child, depth+1)
+	}
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/HackerOS-Linux-System/Universal-Live-Builder/source-code/events"
+)
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func stripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// canned is the event stream testdata/progress.golden was captured
+// against: a build stage with one nested install stage, a log line, a
+// warning, and a rootfs artifact. It deliberately has no progress events,
+// since the rendered progress bar's width and fill characters depend on
+// the bubbles/progress library's own defaults rather than anything this
+// package controls, which would make a byte-exact golden file brittle.
+var canned = []events.Event{
+	{V: 1, Type: events.TypeStageBegin, ID: "build", Stage: "Build ISO"},
+	{V: 1, Type: events.TypeStageBegin, ID: "install", Parent: "build", Stage: "Install packages"},
+	{V: 1, Type: events.TypeLog, Message: "fetched 120 packages"},
+	{V: 1, Type: events.TypeWarning, Message: "low disk space"},
+	{V: 1, Type: events.TypeStageEnd, ID: "install"},
+	{V: 1, Type: events.TypeArtifact, Artifact: "rootfs", Path: "/build/rootfs"},
+	{V: 1, Type: events.TypeStageEnd, ID: "build"},
+}
+
+// TestProgressModelGolden drives Model through a canned event stream and
+// checks the log lines and artifact list it accumulated (the content of
+// View()'s log pane and artifact summary, read from the model rather than
+// from View() so terminal width and color don't matter) against a golden
+// file.
+func TestProgressModelGolden(t *testing.T) {
+	m := New(nil, nil)
+	for _, evt := range canned {
+		m.apply(evt)
+	}
+	m.done = true
+
+	if len(m.stages) != 2 || !m.stages["build"].done || !m.stages["install"].done {
+		t.Fatalf("expected both stages done, got %+v", m.stages)
+	}
+
+	var got strings.Builder
+	got.WriteString(stripANSI(strings.Join(m.logLines, "\n")))
+	got.WriteString("\n")
+	got.WriteString(stripANSI(strings.Join(append([]string{"Artifacts:"}, m.artifact...), "\n")))
+	got.WriteString("\n")
+
+	want, err := os.ReadFile("testdata/progress.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != string(want) {
+		t.Errorf("log+artifact output mismatch:\n got:  %q\nwant: %q", got.String(), string(want))
+	}
+}
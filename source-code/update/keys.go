@@ -0,0 +1,134 @@
+package update
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jedisct1/go-minisign"
+)
+
+// pinnedTrustedKey is the minisign public key baked into every ulb build at
+// release time. It is the root of trust: every other key, including rotated
+// ones, must chain back to a signature made with a key already present in
+// the KeyStore.
+const pinnedTrustedKey = `untrusted comment: minisign public key for ulb releases
+RWSgLvflJhww4Xr4ujSlJeJsTo7IV6zZHk6jP1Cp/wz6lyeKoj4yrRpS`
+
+// trustedKeysDir is the directory under the user's home where additional
+// trusted keys (or a signed rotation bundle) can be dropped.
+const trustedKeysDir = "trusted_keys.d"
+
+// KeyStore holds the set of minisign public keys that ulp accepts release
+// manifests and key-rotation bundles from.
+type KeyStore struct {
+	keys []minisign.PublicKey
+}
+
+// LoadKeyStore builds a KeyStore from the key pinned into the binary plus
+// any additional `*.pub` files under ~/.ulb/trusted_keys.d.
+func LoadKeyStore(homeDir string) (*KeyStore, error) {
+	ks := &KeyStore{}
+	pinned, err := minisign.DecodePublicKey(pinnedTrustedKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse pinned trusted key: %w", err)
+	}
+	ks.keys = append(ks.keys, pinned)
+
+	dir := filepath.Join(homeDir, ".ulb", trustedKeysDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return ks, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read trusted keys dir: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".pub") {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read trusted key %s: %w", e.Name(), err)
+		}
+		key, err := minisign.DecodePublicKey(string(raw))
+		if err != nil {
+			return nil, fmt.Errorf("parse trusted key %s: %w", e.Name(), err)
+		}
+		ks.keys = append(ks.keys, key)
+	}
+	return ks, nil
+}
+
+// VerifyDetached checks sig (a minisign detached signature) against data
+// using any key currently in the store. It returns the key that verified
+// the signature, or an error if none did.
+func (ks *KeyStore) VerifyDetached(data []byte, sig string) (*minisign.PublicKey, error) {
+	parsed, err := minisign.DecodeSignature(sig)
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	}
+	for i := range ks.keys {
+		ok, err := ks.keys[i].Verify(data, parsed)
+		if err == nil && ok {
+			return &ks.keys[i], nil
+		}
+	}
+	return nil, fmt.Errorf("signature did not verify against any trusted key")
+}
+
+// KeyBundle is a set of additional release-signing keys distributed as a
+// unit. It must itself be signed by a key already present in the KeyStore
+// before its keys are merged in, so rotation never bootstraps trust out of
+// thin air.
+type KeyBundle struct {
+	Keys []string `json:"keys"` // minisign public keys, PEM-ish text form
+}
+
+// Rotate verifies bundleSig against bundleJSON using the current store and,
+// on success, appends the bundle's keys to the store. The signing key must
+// already be trusted.
+func (ks *KeyStore) Rotate(bundleJSON []byte, bundleSig string) error {
+	if _, err := ks.VerifyDetached(bundleJSON, bundleSig); err != nil {
+		return fmt.Errorf("key bundle signature: %w", err)
+	}
+	var bundle KeyBundle
+	if err := json.Unmarshal(bundleJSON, &bundle); err != nil {
+		return fmt.Errorf("parse key bundle: %w", err)
+	}
+	for _, raw := range bundle.Keys {
+		key, err := minisign.DecodePublicKey(raw)
+		if err != nil {
+			return fmt.Errorf("parse rotated key: %w", err)
+		}
+		ks.keys = append(ks.keys, key)
+	}
+	return nil
+}
+
+// persistTrustedKeys writes each rotated-in key to ~/.ulb/trusted_keys.d so
+// a future LoadKeyStore trusts it directly, without needing to re-fetch and
+// re-verify the rotation bundle on every run. Each key is named by a short
+// hash of its own text rather than anything from the bundle, so repeated
+// rotations converge rather than piling up duplicate files.
+func persistTrustedKeys(homeDir string, keys []string) error {
+	dir := filepath.Join(homeDir, ".ulb", trustedKeysDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create trusted keys dir: %w", err)
+	}
+	for _, raw := range keys {
+		if _, err := minisign.DecodePublicKey(raw); err != nil {
+			return fmt.Errorf("parse rotated key: %w", err)
+		}
+		sum := sha256.Sum256([]byte(raw))
+		name := "rotated-" + hex.EncodeToString(sum[:8]) + ".pub"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(raw), 0o644); err != nil {
+			return fmt.Errorf("write trusted key %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package update
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Sign produces a detached minisign signature for data using the secret
+// key at keyPath. The go-minisign library used elsewhere in this package
+// is verify-only, so signing shells out to the minisign CLI, which must be
+// on PATH. `ulb sbom` calls it when ULB_SBOM_SIGNING_KEY names a secret
+// key file.
+func Sign(data []byte, keyPath string) (string, error) {
+	tmp, err := os.CreateTemp("", "ulb-sign-*")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+	tmp.Close()
+
+	sigPath := tmp.Name() + ".minisig"
+	defer os.Remove(sigPath)
+
+	cmd := exec.Command("minisign", "-S", "-s", keyPath, "-m", tmp.Name(), "-x", sigPath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("minisign sign: %w: %s", err, out)
+	}
+	sig, err := os.ReadFile(sigPath)
+	if err != nil {
+		return "", fmt.Errorf("read signature: %w", err)
+	}
+	return string(sig), nil
+}
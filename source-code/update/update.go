@@ -0,0 +1,190 @@
+// Package update implements signed, integrity-checked self-update for the
+// ulb CLI and its backend binary. Every artifact is fetched alongside a
+// release manifest and a detached minisign signature; nothing is installed
+// until the signature checks out against a trusted key and the file's
+// SHA256 matches the manifest entry.
+package update
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// Channel selects which release stream artifacts are fetched from.
+type Channel string
+
+const (
+	ChannelStable Channel = "stable"
+	ChannelBeta   Channel = "beta"
+)
+
+const manifestName = "manifest.json"
+
+// keysBundleName is the manifest-adjacent file a channel can optionally
+// publish to rotate in additional trusted signing keys (see KeyStore.Rotate).
+// A channel that has never rotated its keys simply doesn't have one, which
+// is not an error.
+const keysBundleName = "keys.json"
+
+// Manifest describes the signed set of artifacts for a single release.
+type Manifest struct {
+	Version string      `json:"version"`
+	Channel Channel     `json:"channel"`
+	Files   []FileEntry `json:"files"`
+}
+
+// FileEntry is one checksummed artifact in a Manifest.
+type FileEntry struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+}
+
+func (m *Manifest) file(name string) (FileEntry, error) {
+	for _, f := range m.Files {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return FileEntry{}, fmt.Errorf("manifest for %s does not list %q", m.Version, name)
+}
+
+// Updater fetches and verifies release artifacts for one channel.
+type Updater struct {
+	HTTPClient     *http.Client
+	Keys           *KeyStore
+	BaseURL        string // e.g. https://github.com/.../releases/stable/download
+	Channel        Channel
+	CurrentVersion string
+	HomeDir        string // user's home; rotated-in keys are persisted under its .ulb/trusted_keys.d
+}
+
+// NewUpdater builds an Updater that trusts the keys pinned into the binary
+// plus anything under ~/.ulb/trusted_keys.d.
+func NewUpdater(homeDir, currentVersion string, channel Channel) (*Updater, error) {
+	keys, err := LoadKeyStore(homeDir)
+	if err != nil {
+		return nil, fmt.Errorf("load trusted keys: %w", err)
+	}
+	return &Updater{
+		HTTPClient:     http.DefaultClient,
+		Keys:           keys,
+		BaseURL:        fmt.Sprintf("https://github.com/HackerOS-Linux-System/Universal-Live-Builder/releases/%s/download", channel),
+		Channel:        channel,
+		CurrentVersion: currentVersion,
+		HomeDir:        homeDir,
+	}, nil
+}
+
+// rotateKeys fetches this channel's optional signed key-rotation bundle,
+// verifies it against the current KeyStore, merges in any new keys, and
+// persists them under ~/.ulb/trusted_keys.d so future runs trust them
+// without re-fetching the bundle. Any failure to fetch the bundle itself
+// (a 404 from a channel that hasn't published one, but equally any other
+// status or network error) is treated as no bundle and is not an error;
+// a bundle that IS fetched but fails to verify is, since that indicates
+// tampering.
+func (u *Updater) rotateKeys() error {
+	data, err := u.fetch(keysBundleName)
+	if err != nil {
+		return nil
+	}
+	sig, err := u.fetch(keysBundleName + ".minisig")
+	if err != nil {
+		return fmt.Errorf("fetch %s signature: %w", keysBundleName, err)
+	}
+	if err := u.Keys.Rotate(data, string(sig)); err != nil {
+		return fmt.Errorf("rotate trusted keys: %w", err)
+	}
+	var bundle KeyBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return fmt.Errorf("parse key bundle: %w", err)
+	}
+	if err := persistTrustedKeys(u.HomeDir, bundle.Keys); err != nil {
+		return fmt.Errorf("persist rotated keys: %w", err)
+	}
+	return nil
+}
+
+// CheckManifest rotates in any newly published signing keys, then downloads
+// and verifies the manifest for the configured channel without downloading
+// any binaries, and refuses a manifest whose version is not newer than
+// CurrentVersion. Rotating first means a channel that rotates keys and
+// re-signs its manifest in the same release is still verifiable.
+func (u *Updater) CheckManifest() (*Manifest, error) {
+	if err := u.rotateKeys(); err != nil {
+		return nil, err
+	}
+	data, err := u.fetch(manifestName)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := u.fetch(manifestName + ".minisig")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.Keys.VerifyDetached(data, string(sig)); err != nil {
+		return nil, fmt.Errorf("manifest signature: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	if m.Channel != u.Channel {
+		return nil, fmt.Errorf("manifest channel %q does not match requested channel %q", m.Channel, u.Channel)
+	}
+	if u.CurrentVersion != "" && compareVersions(m.Version, u.CurrentVersion) <= 0 {
+		return nil, fmt.Errorf("%w: current %s, available %s", ErrNoUpdate, u.CurrentVersion, m.Version)
+	}
+	return &m, nil
+}
+
+// ErrNoUpdate is returned by CheckManifest when the available release is
+// not newer than CurrentVersion.
+var ErrNoUpdate = fmt.Errorf("no newer release available")
+
+// Apply downloads the named artifact (as listed in manifest.Files), checks
+// its SHA256 against the manifest, and atomically renames it into destPath.
+// The existing file at destPath is left untouched unless verification
+// succeeds.
+func (u *Updater) Apply(manifest *Manifest, name, destPath string) error {
+	entry, err := manifest.file(name)
+	if err != nil {
+		return err
+	}
+	data, err := u.fetch(name)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != entry.SHA256 {
+		return fmt.Errorf("checksum mismatch for %s: manifest says %s, got %s", name, entry.SHA256, got)
+	}
+
+	tmp := destPath + ".new"
+	if err := os.WriteFile(tmp, data, 0o755); err != nil {
+		return fmt.Errorf("write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, destPath); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("install %s: %w", destPath, err)
+	}
+	return os.Chmod(destPath, 0o755)
+}
+
+func (u *Updater) fetch(name string) ([]byte, error) {
+	url := u.BaseURL + "/" + name
+	resp, err := u.HTTPClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: status %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
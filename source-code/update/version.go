@@ -0,0 +1,35 @@
+package update
+
+import (
+	"strconv"
+	"strings"
+)
+
+// compareVersions returns -1, 0 or 1 depending on whether a is older than,
+// equal to, or newer than b. Both are expected in "MAJOR.MINOR.PATCH" form;
+// a missing or non-numeric component is treated as 0 so callers never have
+// to special-case malformed input from a manifest.
+func compareVersions(a, b string) int {
+	as := splitVersion(a)
+	bs := splitVersion(b)
+	for i := 0; i < 3; i++ {
+		if as[i] != bs[i] {
+			if as[i] < bs[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func splitVersion(v string) [3]int {
+	v = strings.TrimPrefix(v, "v")
+	parts := strings.SplitN(v, ".", 3)
+	var out [3]int
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, _ := strconv.Atoi(parts[i])
+		out[i] = n
+	}
+	return out
+}